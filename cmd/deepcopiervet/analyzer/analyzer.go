@@ -0,0 +1,651 @@
+// Package analyzer defines an analysis.Analyzer checking deepcopier struct tags
+// and Copy() calls.
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/ulule/deepcopier"
+)
+
+const pkgPath = "github.com/ulule/deepcopier"
+
+// Analyzer reports unknown deepcopier tag options, unknown fields given to the
+// field option, type mismatches and invalid method signatures.
+var Analyzer = &analysis.Analyzer{
+	Name:     "deepcopier",
+	Doc:      "check deepcopier struct tags and Copy() calls",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var (
+		inspect  = pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+		reported = map[diagnostic]bool{}
+	)
+
+	nodes := []ast.Node{
+		(*ast.StructType)(nil),
+		(*ast.CallExpr)(nil),
+	}
+
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StructType:
+			checkStructTags(pass, n)
+		case *ast.CallExpr:
+			checkCall(pass, n, reported)
+		}
+	})
+
+	return nil, nil
+}
+
+// checkStructTags reports invalid deepcopier struct tags.
+func checkStructTags(pass *analysis.Pass, s *ast.StructType) {
+	for _, f := range s.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+
+		value, ok := reflect.StructTag(tag).Lookup(deepcopier.TagName)
+		if !ok {
+			continue
+		}
+
+		if _, err := deepcopier.ParseTag(value); err != nil {
+			pass.Reportf(f.Tag.Pos(), "invalid deepcopier tag: %s", err)
+		}
+	}
+}
+
+// checkCall checks Copy(src).To(dst) and Copy(dst).From(src) calls. Problems
+// of fields shared by several calls are reported once.
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, reported map[diagnostic]bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) != 1 || (sel.Sel.Name != "To" && sel.Sel.Name != "From") {
+		return
+	}
+
+	if !isDeepCopier(pass.TypesInfo.TypeOf(sel.X)) {
+		return
+	}
+
+	arg := copyArg(pass, sel.X)
+	if arg == nil {
+		return
+	}
+
+	var (
+		reversed = sel.Sel.Name == "From"
+		src      = structType(pass.TypesInfo.TypeOf(arg))
+		dst      = structType(pass.TypesInfo.TypeOf(call.Args[0]))
+	)
+
+	if reversed {
+		src, dst = dst, src
+	}
+
+	if src == nil || dst == nil {
+		return
+	}
+
+	c := &checker{pass: pass, pos: call.Pos(), src: src, dst: dst, reversed: reversed, reported: reported}
+	if reversed {
+		c.checkFrom()
+	} else {
+		c.checkTo()
+	}
+
+	c.checkMethods()
+	c.checkConditions()
+}
+
+// checker checks the mapping between two named struct types.
+type checker struct {
+	pass     *analysis.Pass
+	pos      token.Pos
+	src      *types.Named
+	dst      *types.Named
+	reversed bool
+	reported map[diagnostic]bool
+}
+
+// diagnostic is a reported message at a position.
+type diagnostic struct {
+	pos     token.Pos
+	message string
+}
+
+// checkTo checks destination fields: field options must reference a source
+// field or method, and source fields must be copyable to the destination
+// fields they are copied to.
+func (c *checker) checkTo() {
+	for _, f := range fields(c.dst) {
+		opts := parseTag(f.tag).direction(false)
+		if opts.has(deepcopier.SkipOptionName) {
+			continue
+		}
+
+		name, ok := opts[deepcopier.FieldOptionName]
+		if !ok || name == "" {
+			// Source fields of the same name, unless copied to another field
+			if src := lookupField(c.src, f.name); src != nil {
+				if related, _ := relatedField(c.dst, f.name, false); related != nil && related.v == f.v {
+					c.checkTypes(f, src.Type(), f.v.Type(), opts)
+				}
+			}
+			continue
+		}
+
+		if src := lookupField(c.src, name); src != nil {
+			c.checkTypes(f, src.Type(), f.v.Type(), opts)
+			continue
+		}
+
+		if lookupMethod(c.src, name) == nil {
+			c.reportf(f, "%s.%s: unknown field or method %s in %s", c.dst.Obj().Name(), f.name, name, c.src.Obj().Name())
+		}
+	}
+}
+
+// checkFrom checks source fields: field options must reference a destination
+// field or setter method, and source fields must be copyable to the
+// destination fields they are copied to.
+func (c *checker) checkFrom() {
+	for _, f := range fields(c.src) {
+		opts := parseTag(f.tag).direction(true)
+		if opts.has(deepcopier.SkipOptionName) {
+			continue
+		}
+
+		name, ok := opts[deepcopier.FieldOptionName]
+		if !ok || name == "" {
+			if dst := lookupField(c.dst, f.name); dst != nil {
+				c.checkTypes(f, f.v.Type(), dst.Type(), opts)
+			}
+			continue
+		}
+
+		if dst := lookupField(c.dst, name); dst != nil {
+			c.checkTypes(f, f.v.Type(), dst.Type(), opts)
+			continue
+		}
+
+		setter := opts[deepcopier.SetterOptionName]
+		if setter == "" {
+			setter = "Set" + strings.ToUpper(name[:1]) + name[1:]
+		}
+
+		if lookupMethod(c.dst, setter) == nil {
+			c.reportf(f, "%s.%s: unknown field %s in %s", c.src.Obj().Name(), f.name, name, c.dst.Obj().Name())
+		}
+	}
+}
+
+// checkTypes reports the given tagged field if it cannot be copied.
+func (c *checker) checkTypes(f field, src types.Type, dst types.Type, opts tagOptions) {
+	force := opts.has(deepcopier.ForceOptionName)
+
+	switch {
+	case types.AssignableTo(src, dst):
+	// Dynamic values and registered targets are resolved at runtime
+	case isInterface(src), isInterface(dst) && isNested(src):
+	// Times are formatted and parsed with layout and tz options
+	case isTimeConversion(src, dst) && (force || !isNullable(src)):
+	case isNullable(src), isInterface(dst):
+		if !force {
+			c.reportf(f, "%s: copying %s to %s requires the force option", f.name, src, dst)
+		}
+	case isPointer(src) && types.AssignableTo(src.(*types.Pointer).Elem(), dst):
+	case isNested(src) && isNested(dst):
+	case isCollection(src, dst):
+	default:
+		c.reportf(f, "%s: cannot copy %s to %s", f.name, src, dst)
+	}
+}
+
+// checkMethods checks signatures of source methods copied to destination
+// fields.
+func (c *checker) checkMethods() {
+	mset := types.NewMethodSet(types.NewPointer(c.src))
+
+	for i := 0; i < mset.Len(); i++ {
+		fn, ok := mset.At(i).Obj().(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+
+		f, opts := relatedField(c.dst, fn.Name(), c.reversed)
+		if f == nil || opts.has(deepcopier.SkipOptionName) {
+			continue
+		}
+
+		sig := fn.Type().(*types.Signature)
+
+		if sig.Results().Len() == 0 {
+			c.reportf(*f, "%s.%s: method must return a value to be copied to %s", c.src.Obj().Name(), fn.Name(), f.name)
+			continue
+		}
+
+		if opts.has(deepcopier.ContextOptionName) {
+			if sig.Params().Len() != 1 || !isContextArg(sig.Params().At(0).Type()) {
+				c.reportf(*f, "%s.%s: method must take a deepcopier.Context or a map[string]interface{} (context option of %s)", c.src.Obj().Name(), fn.Name(), f.name)
+			}
+			continue
+		}
+
+		if sig.Params().Len() != 0 {
+			c.reportf(*f, "%s.%s: method must not take arguments without the context option of %s", c.src.Obj().Name(), fn.Name(), f.name)
+		}
+	}
+}
+
+// checkConditions checks signatures of source methods named by if options.
+// Other conditions are registered predicates.
+func (c *checker) checkConditions() {
+	tagged := c.dst
+	if c.reversed {
+		tagged = c.src
+	}
+
+	for _, f := range fields(tagged) {
+		name := parseTag(f.tag)[deepcopier.IfOptionName]
+		if name == "" {
+			continue
+		}
+
+		m := lookupMethod(c.src, name)
+		if m == nil {
+			continue
+		}
+
+		var (
+			sig    = m.Type().(*types.Signature)
+			result = sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+			params = sig.Params().Len() == 0 || (sig.Params().Len() == 1 && isContextArg(sig.Params().At(0).Type()))
+		)
+
+		if !result || !params {
+			c.reportf(f, "%s.%s: method must return a bool (if option of %s)", c.src.Obj().Name(), name, f.name)
+		}
+	}
+}
+
+// reportf reports a diagnostic at the position of the given field, or at the
+// call position when the field is declared in another package.
+func (c *checker) reportf(f field, format string, args ...interface{}) {
+	d := diagnostic{pos: c.pos, message: fmt.Sprintf(format, args...)}
+
+	for _, file := range c.pass.Files {
+		if file.FileStart <= f.v.Pos() && f.v.Pos() < file.FileEnd {
+			d.pos = f.v.Pos()
+			break
+		}
+	}
+
+	if c.reported[d] {
+		return
+	}
+	c.reported[d] = true
+
+	c.pass.Report(analysis.Diagnostic{Pos: d.pos, Message: d.message})
+}
+
+// ----------------------------------------------------------------------------
+// Types
+// ----------------------------------------------------------------------------
+
+// field is an exported struct field.
+type field struct {
+	name string
+	tag  string
+	v    *types.Var
+}
+
+// fields returns exported fields of the given struct type, including fields
+// of embedded structs.
+func fields(t types.Type) []field {
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var result []field
+
+	for i := 0; i < s.NumFields(); i++ {
+		v := s.Field(i)
+		if !v.Exported() {
+			continue
+		}
+
+		if v.Anonymous() {
+			if _, ok := v.Type().Underlying().(*types.Struct); ok {
+				result = append(result, fields(v.Type())...)
+				continue
+			}
+		}
+
+		result = append(result, field{
+			name: v.Name(),
+			tag:  reflect.StructTag(s.Tag(i)).Get(deepcopier.TagName),
+			v:    v,
+		})
+	}
+
+	return result
+}
+
+// lookupField returns the exported field with the given name.
+func lookupField(t types.Type, name string) *types.Var {
+	for _, f := range fields(t) {
+		if f.name == name {
+			return f.v
+		}
+	}
+	return nil
+}
+
+// relatedField returns the first field matching the given name, by field
+// option or by name, in the given copy direction.
+func relatedField(t types.Type, name string, reversed bool) (*field, tagOptions) {
+	for _, f := range fields(t) {
+		opts := parseTag(f.tag).direction(reversed)
+		if v, ok := opts[deepcopier.FieldOptionName]; (ok && v == name) || f.name == name {
+			f := f
+			return &f, opts
+		}
+	}
+	return nil, nil
+}
+
+// lookupMethod returns the method with the given name of the pointer method
+// set of the given type.
+func lookupMethod(t *types.Named, name string) *types.Selection {
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(t.Obj().Pkg(), name)
+}
+
+// isDeepCopier returns true if the given type is *deepcopier.DeepCopier.
+func isDeepCopier(t types.Type) bool {
+	p, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	n, ok := p.Elem().(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := n.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == "DeepCopier"
+}
+
+// copyArg returns the argument given to deepcopier.Copy() in the given
+// builder expression.
+func copyArg(pass *analysis.Pass, expr ast.Expr) ast.Expr {
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			return nil
+		}
+
+		var id *ast.Ident
+
+		switch fun := ast.Unparen(call.Fun).(type) {
+		case *ast.SelectorExpr:
+			if isDeepCopier(pass.TypesInfo.TypeOf(fun.X)) {
+				expr = fun.X
+				continue
+			}
+			id = fun.Sel
+		case *ast.Ident:
+			id = fun
+		default:
+			return nil
+		}
+
+		fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath || fn.Name() != "Copy" || len(call.Args) != 1 {
+			return nil
+		}
+
+		return call.Args[0]
+	}
+}
+
+// structType returns the named struct type of the given (pointer) type.
+func structType(t types.Type) *types.Named {
+	if t == nil {
+		return nil
+	}
+
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	n, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := n.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+
+	return n
+}
+
+// isNullable returns true if the given type implements driver.Valuer.
+func isNullable(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, "Value")
+
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+
+	return sig.Params().Len() == 0 && sig.Results().Len() == 2 && sig.Results().At(1).Type().String() == "error"
+}
+
+// isInterface returns true if the given type is an interface.
+func isInterface(t types.Type) bool {
+	return types.IsInterface(t)
+}
+
+// isPointer returns true if the given type is a pointer.
+func isPointer(t types.Type) bool {
+	_, ok := t.(*types.Pointer)
+	return ok
+}
+
+// isTimeConversion returns true if values of the given types are converted
+// as times: times (time.Time, *time.Time or nullable times) to other times,
+// strings or integers, and strings or integers to times.
+func isTimeConversion(src types.Type, dst types.Type) bool {
+	switch {
+	case isTime(src):
+		return isTime(dst) || isTimeScalar(dst)
+	case isTime(dst):
+		return isTimeScalar(src)
+	}
+
+	return false
+}
+
+// isTime returns true if the given type is time.Time, *time.Time or a
+// nullable type with a time.Time field named Time.
+func isTime(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		return isNamed(p.Elem(), "time", "Time")
+	}
+
+	if isNamed(t, "time", "Time") {
+		return true
+	}
+
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok || !isNullable(t) {
+		return false
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		if f := s.Field(i); f.Name() == "Time" && isNamed(f.Type(), "time", "Time") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTimeScalar returns true if times are converted to and from values of the
+// given type: strings and integers (but durations).
+func isTimeScalar(t types.Type) bool {
+	if isNamed(t, "time", "Duration") {
+		return false
+	}
+
+	b, ok := t.Underlying().(*types.Basic)
+
+	return ok && (b.Info()&types.IsString != 0 || b.Info()&types.IsInteger != 0)
+}
+
+// isNamed returns true if the given type is the named type of the given
+// package path and name.
+func isNamed(t types.Type, pkg string, name string) bool {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := n.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// isCollection returns true if values of the given types are slices or maps
+// copied element by element.
+func isCollection(src types.Type, dst types.Type) bool {
+	switch s := src.Underlying().(type) {
+	case *types.Slice:
+		d, ok := dst.Underlying().(*types.Slice)
+		return ok && isElement(s.Elem()) && isElement(d.Elem())
+	case *types.Map:
+		d, ok := dst.Underlying().(*types.Map)
+		return ok && types.AssignableTo(s.Key(), d.Key()) && isElement(s.Elem()) && isElement(d.Elem())
+	}
+
+	return false
+}
+
+// isElement returns true if collection elements of the given type are copied
+// field by field, or resolved at runtime for interfaces.
+func isElement(t types.Type) bool {
+	return isNested(t) || isInterface(t)
+}
+
+// isNested returns true if values of the given type are copied field by
+// field: structs and maps with string keys.
+func isNested(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		return !isNullable(t) && len(fields(t)) > 0
+	case *types.Map:
+		b, ok := u.Key().Underlying().(*types.Basic)
+		return ok && b.Kind() == types.String
+	}
+
+	return false
+}
+
+// isContextArg returns true if the given type is the type of a context
+// argument: deepcopier.Context or map[string]interface{}.
+func isContextArg(t types.Type) bool {
+	if n, ok := t.(*types.Named); ok {
+		obj := n.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == "Context"
+	}
+
+	return isContextMap(t)
+}
+
+// isContextMap returns true if the given type is map[string]interface{}.
+func isContextMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+
+	b, ok := m.Key().(*types.Basic)
+	if !ok || b.Kind() != types.String {
+		return false
+	}
+
+	i, ok := m.Elem().Underlying().(*types.Interface)
+
+	return ok && i.Empty()
+}
+
+// parseTag parses the given deepcopier tag value, ignoring errors reported
+// on struct declarations.
+func parseTag(value string) tagOptions {
+	opts, _ := deepcopier.ParseTag(value)
+	return tagOptions(opts)
+}
+
+// tagOptions are parsed deepcopier tag options.
+type tagOptions deepcopier.TagOptions
+
+// direction returns the options used in the given copy direction: the to (or
+// from, when reversed) option replaces the field option, and writeonly (or
+// readonly, when reversed) fields are skipped.
+func (o tagOptions) direction(reversed bool) tagOptions {
+	name, skip := deepcopier.ToOptionName, deepcopier.WriteOnlyOptionName
+	if reversed {
+		name, skip = deepcopier.FromOptionName, deepcopier.ReadOnlyOptionName
+	}
+
+	options := make(tagOptions, len(o)+1)
+	for k, v := range o {
+		options[k] = v
+	}
+
+	if v, ok := o[name]; ok {
+		options[deepcopier.FieldOptionName] = v
+	}
+
+	if o.has(skip) {
+		options[deepcopier.SkipOptionName] = ""
+	}
+
+	return options
+}
+
+// has returns true if the given option is set.
+func (o tagOptions) has(key string) bool {
+	_, ok := o[key]
+	return ok
+}
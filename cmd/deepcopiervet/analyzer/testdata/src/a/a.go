@@ -0,0 +1,192 @@
+package a
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ulule/deepcopier"
+)
+
+type User struct {
+	Username string
+	Email    sql.NullString
+	Age      int
+}
+
+func (u *User) APIURL(ctx map[string]interface{}) string { return "" }
+
+func (u User) FullName(prefix string) string { return "" }
+
+func (u User) Touch() {}
+
+type UserResource struct {
+	Name     string `deepcopier:"field:Username"`
+	Mail     string `deepcopier:"field:Emial"` // want `UserResource.Mail: unknown field or method Emial in User`
+	Email    string `deepcopier:"field:Email"` // want `Email: copying database/sql.NullString to string requires the force option`
+	Years    string `deepcopier:"field:Age"`   // want `Years: cannot copy int to string`
+	APIURL   string `deepcopier:"context"`
+	FullName string // want `User.FullName: method must not take arguments without the context option of FullName`
+	Touch    string // want `User.Touch: method must return a value to be copied to Touch`
+	Ignored  string `deepcopier:"skip; foce"` // want `invalid deepcopier tag: unknown option "foce"`
+}
+
+type Duplicated struct {
+	Name string `deepcopier:"field:Username; field:Login"` // want `invalid deepcopier tag: duplicated option "field"`
+}
+
+type Payload struct {
+	Login    string `deepcopier:"field:Username"`
+	Typo     string `deepcopier:"field:Usrname"`             // want `Payload.Typo: unknown field Usrname in User` `Payload.Typo: unknown field or method Usrname in User`
+	Computed string `deepcopier:"field:FullName; readonly"`  // want `User.FullName: method must not take arguments without the context option of Computed`
+	Alias    string `deepcopier:"to:Usrname; from:Username"` // want `Payload.Alias: unknown field or method Usrname in User`
+}
+
+type ValidResource struct {
+	Name   string         `deepcopier:"field:Username"`
+	Email  string         `deepcopier:"field:Email;force"`
+	Mail   sql.NullString `deepcopier:"field:Email"`
+	APIURL string         `deepcopier:"context"`
+}
+
+type Item interface {
+	ItemID() int
+}
+
+type Feed struct {
+	Item  Item
+	Post  User
+	Count int
+	Items []Item
+}
+
+type FeedResource struct {
+	Entry ValidResource `deepcopier:"field:Item"`
+	Owner Item          `deepcopier:"field:Post"`
+	Total Item          `deepcopier:"field:Count"` // want `Total: copying int to a.Item requires the force option`
+	Items []ValidResource
+}
+
+type Order struct {
+	Items []User
+}
+
+type OrderPayload struct {
+	Lines []Feed `deepcopier:"field:Items; mergekey:Username; orphan:delete"`
+	Count []int  `deepcopier:"field:Items"` // want `Count: cannot copy \[\]a.User to \[\]int`
+}
+
+func copies() {
+	user := &User{}
+
+	deepcopier.Copy(user).To(&UserResource{})
+
+	deepcopier.Copy(user).WithContext(nil).From(&Payload{})
+
+	deepcopier.Copy(*user).To(&ValidResource{})
+
+	deepcopier.Copy(user).To(&Payload{})
+
+	// Problems of shared fields are reported once
+	deepcopier.Copy(*user).To(&UserResource{})
+}
+
+type Member struct {
+	Name string
+	Age  int
+	Tags []string
+}
+
+type MemberResource struct {
+	Name string
+	Age  string // want `Age: cannot copy int to string`
+	Tags []string
+}
+
+type MemberSummary struct {
+	Years int `deepcopier:"field:Age"`
+	Age   string
+}
+
+type MemberPayload struct {
+	Name string
+	Age  string   // want `Age: cannot copy string to int`
+	Tags []string `deepcopier:"skip"`
+}
+
+func members() {
+	deepcopier.Copy(&Member{}).To(&MemberResource{})
+
+	deepcopier.Copy(&Member{}).To(&MemberSummary{})
+
+	deepcopier.Copy(&Member{}).From(&MemberPayload{})
+}
+
+type TypedContext struct{}
+
+func (TypedContext) APIURL(ctx deepcopier.Context) string { return "" }
+
+type TypedContextResource struct {
+	APIURL string `deepcopier:"context"`
+}
+
+type BadContext struct{}
+
+type BadContextResource struct {
+	APIURL string `deepcopier:"context"` // want `BadContext.APIURL: method must take a deepcopier.Context or a map\[string\]interface\{\} \(context option of APIURL\)`
+}
+
+func (BadContext) APIURL(ctx string) string { return "" }
+
+func badContext() {
+	deepcopier.Copy(BadContext{}).To(&BadContextResource{})
+
+	deepcopier.Copy(&Feed{}).To(&FeedResource{})
+
+	deepcopier.Copy(&Order{}).To(&OrderPayload{})
+
+	deepcopier.Copy(&Profile{}).To(&ProfileResource{})
+
+	deepcopier.Copy(TypedContext{}).To(&TypedContextResource{})
+}
+
+type Post struct {
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+	PublishedAt sql.NullTime
+	Timeout     time.Duration
+}
+
+type PostResource struct {
+	CreatedAt   string        `deepcopier:"field:CreatedAt; layout:DateOnly"`
+	UpdatedAt   int64         `deepcopier:"field:UpdatedAt; layout:unixmilli"`
+	PublishedAt *time.Time    `deepcopier:"field:PublishedAt; force"`
+	Published   string        `deepcopier:"field:PublishedAt"` // want `Published: copying database/sql.NullTime to string requires the force option`
+	Timeout     string        `deepcopier:"field:Timeout"`     // want `Timeout: cannot copy time.Duration to string`
+	Expiry      time.Duration `deepcopier:"field:CreatedAt"`   // want `Expiry: cannot copy time.Time to time.Duration`
+}
+
+type PostPayload struct {
+	CreatedAt string `deepcopier:"field:CreatedAt; layout:DateOnly; tz:Europe/Paris"`
+	UpdatedAt uint32 `deepcopier:"field:UpdatedAt"`
+}
+
+func times() {
+	deepcopier.Copy(&Post{}).To(&PostResource{})
+
+	deepcopier.Copy(&Post{}).From(&PostPayload{})
+}
+
+type Profile struct {
+	Email string
+	Bio   string
+}
+
+func (p *Profile) IsPublic(ctx deepcopier.Context) bool { return true }
+
+func (p *Profile) IsHidden() string { return "" }
+
+type ProfileResource struct {
+	Email string `deepcopier:"if:IsPublic"`
+	Bio   string `deepcopier:"if:IsHidden"` // want `Profile.IsHidden: method must return a bool \(if option of Bio\)`
+	Name  string `deepcopier:"if:is_owner"`
+}
@@ -0,0 +1,15 @@
+package deepcopier
+
+type DeepCopier struct{}
+
+type Context struct {
+	Values map[string]interface{}
+}
+
+func Copy(src interface{}) *DeepCopier { return &DeepCopier{} }
+
+func (dc *DeepCopier) WithContext(ctx map[string]interface{}) *DeepCopier { return dc }
+
+func (dc *DeepCopier) To(dst interface{}) error { return nil }
+
+func (dc *DeepCopier) From(src interface{}) error { return nil }
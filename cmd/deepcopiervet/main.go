@@ -0,0 +1,16 @@
+// Command deepcopiervet checks deepcopier struct tags and Copy() calls.
+//
+// Usage:
+//
+//	go vet -vettool=$(which deepcopiervet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/ulule/deepcopier/cmd/deepcopiervet/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
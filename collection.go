@@ -0,0 +1,219 @@
+package deepcopier
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// isCollection returns true if values of the given types are slices or maps
+// copied element by element: elements are structs (or maps with string keys),
+// interfaces holding them or interfaces implemented by registered targets,
+// and map keys are assignable.
+func isCollection(src reflect.Type, dst reflect.Type) bool {
+	switch {
+	case src.Kind() == reflect.Slice && dst.Kind() == reflect.Slice:
+	case src.Kind() == reflect.Map && dst.Kind() == reflect.Map:
+		if !src.Key().AssignableTo(dst.Key()) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	var (
+		srcElem = indirectType(src.Elem())
+		dstElem = indirectType(dst.Elem())
+	)
+
+	// Elements copied to registered targets
+	if dstElem.Kind() == reflect.Interface {
+		return !src.Elem().AssignableTo(dst.Elem()) && isPolymorphic(dstElem) &&
+			(srcElem.Kind() == reflect.Interface || isNestedType(srcElem))
+	}
+
+	// Dynamic values of elements
+	if srcElem.Kind() == reflect.Interface {
+		return dstElem.Kind() == reflect.Struct && isNestedType(dstElem)
+	}
+
+	if srcElem.Kind() != reflect.Struct && dstElem.Kind() != reflect.Struct {
+		return false
+	}
+
+	return isNestedType(srcElem) && isNestedType(dstElem)
+}
+
+// copyCollection returns a new slice or map of the given type with elements
+// of the given slice or map copied into new elements.
+func copyCollection(t reflect.Type, src reflect.Value, options Options) (reflect.Value, error) {
+	if src.IsNil() {
+		return reflect.Zero(t), nil
+	}
+
+	// Elements are new values and nested collections are copied sequentially
+	elemOptions := options.withoutChanges()
+	elemOptions.Parallel = 0
+
+	if t.Kind() == reflect.Slice {
+		v := reflect.MakeSlice(t, src.Len(), src.Len())
+
+		err := forEach(src.Len(), options, func(i int) error {
+			if err := copyElement(v.Index(i), src.Index(i), elemOptions.withPath(strconv.Itoa(i))); err != nil {
+				return newFieldError(strconv.Itoa(i), err)
+			}
+			return nil
+		})
+
+		return v, err
+	}
+
+	var (
+		keys   = src.MapKeys()
+		values = make([]reflect.Value, len(keys))
+	)
+
+	err := forEach(len(keys), options, func(i int) error {
+		var (
+			key  = fmt.Sprint(keys[i].Interface())
+			elem = reflect.New(t.Elem()).Elem()
+		)
+
+		if err := copyElement(elem, src.MapIndex(keys[i]), elemOptions.withPath(key)); err != nil {
+			return newFieldError(key, err)
+		}
+
+		values[i] = elem
+		return nil
+	})
+
+	v := reflect.MakeMapWithSize(t, len(keys))
+	for i, key := range keys {
+		if values[i].IsValid() {
+			v.SetMapIndex(key.Convert(t.Key()), values[i])
+		}
+	}
+
+	return v, err
+}
+
+// copyElement copies the given element of a source collection into the given
+// new element.
+func copyElement(dst reflect.Value, src reflect.Value, options Options) error {
+	if dst.Kind() == reflect.Interface {
+		_, err := copyInterface(dst, "", src, false, options)
+		return err
+	}
+
+	src = dynamicValue(src)
+	if !src.IsValid() || isNil(src) {
+		return nil
+	}
+
+	v, found, err := checkCycle(src, reflect.PtrTo(indirectType(dst.Type())), options)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if v.IsValid() && dst.Kind() == reflect.Ptr {
+			dst.Set(v)
+		}
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		ptr := reflect.New(dst.Type().Elem())
+		if err := process(ptr.Interface(), src.Interface(), options); err != nil {
+			return err
+		}
+
+		dst.Set(ptr)
+		return nil
+	}
+
+	return process(dst.Addr().Interface(), src.Interface(), options)
+}
+
+// forEach calls the given function for each index up to n, in goroutines
+// of a pool of options.Parallel workers if greater than one. It stops at the
+// error of the lowest index, unless in strict mode where all errors are
+// returned.
+func forEach(n int, options Options, fn func(i int) error) error {
+	var (
+		errs    = make([]error, n)
+		workers = options.Parallel
+	)
+
+	if workers > n {
+		workers = n
+	}
+
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if errs[i] = fn(i); errs[i] != nil && !options.Strict {
+				break
+			}
+		}
+
+		return collectErrors(errs, options.Strict)
+	}
+
+	var (
+		wg     sync.WaitGroup
+		next   = int64(-1)
+		failed int32
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				if !options.Strict && atomic.LoadInt32(&failed) == 1 {
+					return
+				}
+
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+
+				if errs[i] = fn(i); errs[i] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return collectErrors(errs, options.Strict)
+}
+
+// collectErrors returns the first of the given errors, or all of them.
+func collectErrors(errs []error, all bool) error {
+	var result Errors
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if !all {
+			return err
+		}
+
+		result = append(result, err)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
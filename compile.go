@@ -0,0 +1,367 @@
+package deepcopier
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// compiledPlan is the mapping of a source type to a destination type,
+// discovered once from struct tags and profiles and reused by all copies of
+// these types.
+type compiledPlan struct {
+	// fields are the source fields copied to destination fields.
+	fields []fieldPlan
+	// methods are the source methods copied to destination fields.
+	methods []methodPlan
+	// skipped are the source fields and methods matching a destination
+	// field which are never copied.
+	skipped []skippedPlan
+	// fromContext are context keys of destination fields by field name.
+	fromContext map[string]string
+	// defaults are default options of destination fields by field name.
+	defaults map[string]defaultValue
+	// err is the first invalid option found while compiling the plan,
+	// returned by all copies of the types.
+	err error
+	// profile is the registered profile of the types.
+	profile *profile
+	// flat is true if all fields of source and destination structs are
+	// assigned, so that no nested value is copied.
+	flat bool
+}
+
+// fieldPlan is a source field copied to a destination field.
+type fieldPlan struct {
+	src      reflect.StructField
+	dst      string
+	dstField reflect.StructField
+	dstFound bool
+	options  TagOptions
+	// cond is the condition of the if option.
+	cond *condition
+	// tz is the location of the tz option, nil if missing or unknown.
+	tz *time.Location
+	// assign is true if the source value is assigned as is to the
+	// destination field, without any conversion.
+	assign bool
+}
+
+// methodPlan is a source method copied to a destination field.
+type methodPlan struct {
+	name     string
+	index    int
+	dst      string
+	dstField reflect.StructField
+	options  TagOptions
+	// cond is the condition of the if option.
+	cond *condition
+	// tz is the location of the tz option, nil if missing or unknown.
+	tz *time.Location
+	// embedded are the index paths of embedded pointers the method is
+	// promoted through, outermost first.
+	embedded [][]int
+}
+
+// skippedPlan is a source field or method never copied to the matching
+// destination field.
+type skippedPlan struct {
+	src     string
+	dst     string
+	method  bool
+	options TagOptions
+	reason  string
+}
+
+// planKey is the key of compiled plans.
+type planKey struct {
+	src      reflect.Type
+	dst      reflect.Type
+	reversed bool
+}
+
+// compiledPlans are compiled plans by planKey.
+var compiledPlans sync.Map
+
+// getCompiledPlan returns the compiled plan of the given types, compiling it
+// on first use.
+func getCompiledPlan(src reflect.Type, dst reflect.Type, reversed bool) *compiledPlan {
+	key := planKey{src: indirectType(src), dst: indirectType(dst), reversed: reversed}
+
+	if p, ok := compiledPlans.Load(key); ok {
+		return p.(*compiledPlan)
+	}
+
+	p, _ := compiledPlans.LoadOrStore(key, compilePlan(key.src, key.dst, reversed))
+
+	return p.(*compiledPlan)
+}
+
+// resetCompiledPlans removes compiled plans, which must be compiled again
+// once profiles or extensions change.
+func resetCompiledPlans() {
+	compiledPlans.Range(func(key, _ interface{}) bool {
+		compiledPlans.Delete(key)
+		return true
+	})
+}
+
+// compilePlan returns the plan of the given types.
+func compilePlan(src reflect.Type, dst reflect.Type, reversed bool) *compiledPlan {
+	p := &compiledPlan{
+		fromContext: map[string]string{},
+		profile:     getProfile(src, dst),
+	}
+
+	p.defaults, p.err = compileDefaults(dst, src, reversed)
+
+	for name, tagOptions := range getDestinationOptions(dst, src, reversed) {
+		if key := tagOptions[FromContextOptionName]; key != "" {
+			p.fromContext[name] = key
+		}
+	}
+
+	if src.Kind() != reflect.Struct || dst.Kind() != reflect.Struct {
+		return p
+	}
+
+	for _, f := range getTypeFieldNames(src) {
+		var (
+			srcField, found = src.FieldByName(f)
+			dstFieldName    = srcField.Name
+			tagOptions      TagOptions
+		)
+
+		if !found {
+			continue
+		}
+
+		if reversed {
+			tagOptions = getTagOptions(srcField.Tag.Get(TagName)).direction(true)
+			if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
+				dstFieldName = v
+			}
+		} else {
+			if name, opts := getTypeRelatedField(dst, srcField.Name, false); name != "" {
+				dstFieldName, tagOptions = name, opts
+			}
+		}
+
+		dstFieldName, tagOptions = p.profile.resolve(dst, srcField.Name, dstFieldName, tagOptions, reversed)
+		if dstFieldName == "" {
+			continue
+		}
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			p.skip(srcField.Name, dstFieldName, false, tagOptions, "skip option")
+			continue
+		}
+
+		dstField, dstFound := dst.FieldByName(dstFieldName)
+		if dstFound && skipExtensionField(dst, dstField) {
+			p.skip(srcField.Name, dstFieldName, false, tagOptions, "skipped by extension")
+			continue
+		}
+
+		// Missing fields without setter are never copied
+		if _, ok := tagOptions[SetterOptionName]; !ok && !dstFound && !hasSetter(dst, dstFieldName) {
+			continue
+		}
+
+		p.fields = append(p.fields, fieldPlan{
+			src:      srcField,
+			dst:      dstFieldName,
+			dstField: dstField,
+			dstFound: dstFound,
+			options:  tagOptions,
+			cond:     compileCondition(src, tagOptions[IfOptionName]),
+			tz:       loadLocation(tagOptions),
+			assign:   p.isAssignment(srcField, dstField, dstFound, tagOptions),
+		})
+	}
+
+	p.flat = true
+	for _, f := range p.fields {
+		p.flat = p.flat && f.assign
+	}
+
+	for _, m := range getTypeMethodNames(src) {
+		name, tagOptions := getTypeRelatedField(dst, m, reversed)
+
+		name, tagOptions = p.profile.resolve(dst, m, name, tagOptions, reversed)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			p.skip(m, name, true, tagOptions, "skip option")
+			continue
+		}
+
+		dstField, _ := dst.FieldByName(name)
+		if skipExtensionField(dst, dstField) {
+			p.skip(m, name, true, tagOptions, "skipped by extension")
+			continue
+		}
+
+		method, _ := reflect.PtrTo(src).MethodByName(m)
+
+		p.methods = append(p.methods, methodPlan{
+			name:     m,
+			index:    method.Index,
+			dst:      name,
+			dstField: dstField,
+			options:  tagOptions,
+			cond:     compileCondition(src, tagOptions[IfOptionName]),
+			tz:       loadLocation(tagOptions),
+			embedded: embeddedPointers(src, m),
+		})
+	}
+
+	return p
+}
+
+// skip records the given source field or method as never copied to the given
+// destination field for the given reason.
+func (p *compiledPlan) skip(src string, dst string, method bool, tagOptions TagOptions, reason string) {
+	p.skipped = append(p.skipped, skippedPlan{src: src, dst: dst, method: method, options: tagOptions, reason: reason})
+}
+
+// isAssignment returns true if values of the given source field are assigned
+// as is to the given destination field: both fields have the same basic type
+// (bool, number or string) and no setter, converter or extension applies.
+func (p *compiledPlan) isAssignment(src reflect.StructField, dst reflect.StructField, found bool, tagOptions TagOptions) bool {
+	if !found || dst.PkgPath != "" || src.Type != dst.Type || !isBasicKind(src.Type.Kind()) {
+		return false
+	}
+
+	if _, ok := tagOptions[SetterOptionName]; ok {
+		return false
+	}
+
+	if p.profile.converter(dst.Name) != nil {
+		return false
+	}
+
+	_, ok := convertExtension(reflect.Zero(src.Type), dst.Type)
+
+	return !ok
+}
+
+// isBasicKind returns true if the given kind is a bool, a number or a string.
+func isBasicKind(k reflect.Kind) bool {
+	return (k >= reflect.Bool && k <= reflect.Complex128) || k == reflect.String
+}
+
+// embeddedPointers returns the index paths of the embedded pointer fields of
+// the given struct type through which the given method is promoted, outermost
+// first. Methods declared at a shallower depth shadow the embedded ones and
+// are not promoted.
+func embeddedPointers(t reflect.Type, method string) [][]int {
+	if t.Kind() != reflect.Struct || declaresMethod(t, method) {
+		return nil
+	}
+
+	var (
+		paths [][]int
+		depth = -1
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+
+		ft := indirectType(f.Type)
+
+		d, ok := methodDepth(ft, method)
+		if !ok || (depth >= 0 && d >= depth) {
+			continue
+		}
+
+		depth, paths = d, nil
+		if f.Type.Kind() == reflect.Ptr {
+			paths = append(paths, []int{i})
+		}
+
+		for _, p := range embeddedPointers(ft, method) {
+			paths = append(paths, append([]int{i}, p...))
+		}
+	}
+
+	return paths
+}
+
+// methodDepth returns the depth of the embedded field declaring the given
+// method of the given type, zero if the type declares it. It returns false if
+// the type has no such method.
+func methodDepth(t reflect.Type, method string) (int, bool) {
+	if _, ok := reflect.PtrTo(t).MethodByName(method); !ok {
+		return 0, false
+	}
+
+	if t.Kind() != reflect.Struct || declaresMethod(t, method) {
+		return 0, true
+	}
+
+	var (
+		depth = 0
+		found bool
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+
+		if d, ok := methodDepth(indirectType(f.Type), method); ok && (!found || d+1 < depth) {
+			depth, found = d+1, true
+		}
+	}
+
+	return depth, found
+}
+
+// declaresMethod returns true if the given method is declared by the given
+// type. Methods promoted from embedded fields are wrappers generated by the
+// compiler, which have no source file.
+func declaresMethod(t reflect.Type, method string) bool {
+	for _, typ := range []reflect.Type{t, reflect.PtrTo(t)} {
+		m, ok := typ.MethodByName(method)
+		if !ok {
+			continue
+		}
+
+		fn := runtime.FuncForPC(m.Func.Pointer())
+		if fn == nil {
+			return true
+		}
+
+		if file, _ := fn.FileLine(m.Func.Pointer()); file != "<autogenerated>" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasNilEmbedded returns true if one of the embedded pointers of the given
+// index paths is nil in the given struct.
+func hasNilEmbedded(v reflect.Value, paths [][]int) bool {
+	for _, p := range paths {
+		if v.FieldByIndex(p).IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSetter returns true if the given struct type has a setter method for the
+// given field.
+func hasSetter(t reflect.Type, fieldName string) bool {
+	_, ok := reflect.PtrTo(t).MethodByName(setterName(fieldName))
+	return ok
+}
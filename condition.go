@@ -0,0 +1,116 @@
+package deepcopier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnknownCondition is returned when the condition of an if option is
+// neither a source method nor a registered predicate.
+var ErrUnknownCondition = errors.New("unknown condition")
+
+// Predicate is a named condition of if options, called with the source being
+// copied (a pointer to a struct) and the copy context.
+type Predicate func(src interface{}, ctx Context) bool
+
+var (
+	predicatesMu sync.RWMutex
+	predicates   = map[string]Predicate{}
+)
+
+// RegisterPredicate registers the given predicate under the given name, used
+// by if options which do not name a method of the source.
+func RegisterPredicate(name string, fn Predicate) {
+	predicatesMu.Lock()
+	defer predicatesMu.Unlock()
+
+	predicates[name] = fn
+}
+
+// getPredicate returns the predicate registered under the given name.
+func getPredicate(name string) Predicate {
+	predicatesMu.RLock()
+	defer predicatesMu.RUnlock()
+
+	return predicates[name]
+}
+
+// condition is the condition of a field copied only if it holds.
+type condition struct {
+	name string
+	// method is the index of the source method, or -1 for predicates.
+	method int
+	// context is the type of the context argument of the method, if any.
+	context reflect.Type
+	// embedded are the index paths of embedded pointers the method is
+	// promoted through.
+	embedded [][]int
+	err      error
+}
+
+// compileCondition returns the condition of the given if option for sources
+// of the given struct type, nil if none. Source methods take precedence over
+// registered predicates.
+func compileCondition(src reflect.Type, name string) *condition {
+	if name == "" {
+		return nil
+	}
+
+	c := &condition{name: name, method: -1}
+
+	method, ok := reflect.PtrTo(src).MethodByName(name)
+	if !ok {
+		return c
+	}
+
+	t := method.Type
+	if t.NumOut() != 1 || t.Out(0).Kind() != reflect.Bool || t.NumIn() > 2 ||
+		(t.NumIn() == 2 && !isContextType(t.In(1))) {
+		c.err = fmt.Errorf("invalid condition method signature %s", t)
+		return c
+	}
+
+	c.method, c.embedded = method.Index, embeddedPointers(src, name)
+	if t.NumIn() == 2 {
+		c.context = t.In(1)
+	}
+
+	return c
+}
+
+// holds returns true if the condition holds for the given source receiver
+// copied to the given field of the destination struct, or if there is no
+// condition.
+func (c *condition) holds(dst reflect.Value, receiver reflect.Value, name string, options Options) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	if c.err != nil {
+		return false, c.err
+	}
+
+	if c.method >= 0 {
+		// Conditions promoted through nil embedded pointers do not hold
+		if hasNilEmbedded(receiver.Elem(), c.embedded) {
+			return false, nil
+		}
+
+		var args []reflect.Value
+		if c.context != nil {
+			ctx := newContext(dst.Addr().Interface(), receiver.Interface(), joinPath(options.path, name), options)
+			args = []reflect.Value{contextArg(c.context, ctx)}
+		}
+
+		return receiver.Method(c.method).Call(args)[0].Bool(), nil
+	}
+
+	fn := getPredicate(c.name)
+	if fn == nil {
+		return false, fmt.Errorf("%w %q", ErrUnknownCondition, c.name)
+	}
+
+	return fn(receiver.Interface(), newContext(dst.Addr().Interface(), receiver.Interface(), joinPath(options.path, name), options)), nil
+}
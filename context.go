@@ -0,0 +1,146 @@
+package deepcopier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrMissingContextKey is returned in strict mode when a key of a fromcontext
+// option is missing from the context.
+var ErrMissingContextKey = errors.New("missing context key")
+
+// ContextKey is the type of context.Context keys looked up by the fromcontext
+// option. Plain string keys are looked up too.
+type ContextKey string
+
+// Context is the copy context given to lifecycle hooks, predicates and
+// source methods taking a Context argument.
+type Context struct {
+	// Values given to WithContext() method.
+	Values map[string]interface{}
+	// StdContext given to WithStdContext() method, nil if none.
+	StdContext context.Context
+	// Path is the dot-separated path of the copied field, or of the copied
+	// struct for hooks (empty for the top-level copy).
+	Path string
+	// Src is the source struct being copied.
+	Src interface{}
+	// Dst is the destination struct being copied.
+	Dst interface{}
+}
+
+// contextType is the type of Context arguments of source methods.
+var contextType = reflect.TypeOf(Context{})
+
+// newContext returns the context of a copy of the given source struct to the
+// given destination struct.
+func newContext(dst interface{}, src interface{}, path string, options Options) Context {
+	return Context{
+		Values:     options.Context,
+		StdContext: options.StdContext,
+		Path:       path,
+		Src:        src,
+		Dst:        dst,
+	}
+}
+
+// Value returns the value of the given key from the context values, or from
+// the context.Context, nil if missing.
+func (c Context) Value(key string) interface{} {
+	v, _ := lookupContext(key, Options{Context: c.Values, StdContext: c.StdContext})
+	return v
+}
+
+// String returns the value of the given key converted to a string, empty if
+// missing or not a string.
+func (c Context) String(key string) string {
+	v, ok := convert(reflect.ValueOf(c.Value(key)), reflect.TypeOf(""))
+	if !ok {
+		return ""
+	}
+
+	return v.String()
+}
+
+// Int returns the value of the given key converted to an int, zero if missing
+// or not a number.
+func (c Context) Int(key string) int {
+	v, ok := convert(reflect.ValueOf(c.Value(key)), reflect.TypeOf(0))
+	if !ok {
+		return 0
+	}
+
+	return int(v.Int())
+}
+
+// isContextType returns true if the given type is the type of a context
+// argument of source methods: Context or map[string]interface{}.
+func isContextType(t reflect.Type) bool {
+	return t == contextType || t == reflect.TypeOf(map[string]interface{}{})
+}
+
+// contextArg returns the argument of a source method taking a context of the
+// given type: the given Context, or its values for methods taking a
+// map[string]interface{}.
+func contextArg(t reflect.Type, ctx Context) reflect.Value {
+	if t == contextType {
+		return reflect.ValueOf(ctx)
+	}
+
+	return reflect.ValueOf(ctx.Values)
+}
+
+// lookupContext returns the value of the given key from the context values,
+// or from the context.Context.
+func lookupContext(key string, options Options) (interface{}, bool) {
+	if v, ok := options.Context[key]; ok {
+		return v, true
+	}
+
+	if options.StdContext == nil {
+		return nil, false
+	}
+
+	if v := options.StdContext.Value(ContextKey(key)); v != nil {
+		return v, true
+	}
+
+	if v := options.StdContext.Value(key); v != nil {
+		return v, true
+	}
+
+	return nil, false
+}
+
+// applyFromContext sets destination fields with a fromcontext option to the
+// context value of the given key.
+func applyFromContext(dst reflect.Value, plan *compiledPlan, options Options) error {
+	for name, key := range plan.fromContext {
+		field := dst.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+
+		value, ok := lookupContext(key, options)
+		if !ok {
+			if options.Strict {
+				return newFieldError(name, fmt.Errorf("%w %q", ErrMissingContextKey, key))
+			}
+			continue
+		}
+
+		v, ok := convert(reflect.ValueOf(value), field.Type())
+		if !ok {
+			if options.Strict {
+				return newFieldError(name, fmt.Errorf("cannot use context value %v as %s", value, field.Type()))
+			}
+			continue
+		}
+
+		setField(field, name, options, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package deepcopier
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"reflect"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// convert converts the given value to the given type.
+// It returns false if no conversion is known between both types.
+func convert(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if !value.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	// Unwrap interfaces
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		return convert(value.Elem(), t)
+	}
+
+	// Extensions
+	if v, ok := convertExtension(value, t); ok {
+		return v, true
+	}
+
+	if value.Type().AssignableTo(t) {
+		return value, true
+	}
+
+	// Scanner (sql.NullString, null.String, ...)
+	if reflect.PtrTo(t).Implements(scannerType) {
+		ptr := reflect.New(t)
+		if err := ptr.Interface().(sql.Scanner).Scan(value.Interface()); err == nil {
+			return ptr.Elem(), true
+		}
+	}
+
+	// Value -> Ptr
+	if t.Kind() == reflect.Ptr {
+		v, ok := convert(value, t.Elem())
+		if !ok {
+			return reflect.Value{}, false
+		}
+
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(v)
+
+		return ptr, true
+	}
+
+	// Ptr -> Value
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		return convert(value.Elem(), t)
+	}
+
+	// Valuer -> Value
+	if isNullableType(value.Type()) {
+		v, err := value.Interface().(driver.Valuer).Value()
+		if err != nil || v == nil {
+			return reflect.Value{}, false
+		}
+		return convert(reflect.ValueOf(v), t)
+	}
+
+	switch {
+	case isInt(value.Kind()) && isNumber(t.Kind()):
+		return convertInt(value.Int(), t)
+	case isUint(value.Kind()) && isNumber(t.Kind()):
+		n := value.Uint()
+		if n > math.MaxInt64 {
+			if isUint(t.Kind()) && !reflect.Zero(t).OverflowUint(n) {
+				return value.Convert(t), true
+			}
+			return reflect.Value{}, false
+		}
+		return convertInt(int64(n), t)
+	case isFloat(value.Kind()) && isNumber(t.Kind()):
+		f := value.Float()
+		if isFloat(t.Kind()) {
+			return value.Convert(t), true
+		}
+		// Only integral floats (e.g. JSON numbers) fit into integers
+		if f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+			return reflect.Value{}, false
+		}
+		return convertInt(int64(f), t)
+	case value.Kind() == reflect.String && t.Kind() == reflect.String,
+		value.Kind() == reflect.Bool && t.Kind() == reflect.Bool:
+		return value.Convert(t), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// convertInt converts the given integer to the given numeric type,
+// checking for overflows.
+func convertInt(n int64, t reflect.Type) (reflect.Value, bool) {
+	v := reflect.New(t).Elem()
+
+	switch {
+	case isInt(t.Kind()):
+		if v.OverflowInt(n) {
+			return reflect.Value{}, false
+		}
+		v.SetInt(n)
+	case isUint(t.Kind()):
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return reflect.Value{}, false
+		}
+		v.SetUint(uint64(n))
+	case isFloat(t.Kind()):
+		v.SetFloat(float64(n))
+	default:
+		return reflect.Value{}, false
+	}
+
+	return v, true
+}
+
+// isInt returns true if the given kind is a signed integer one.
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+// isUint returns true if the given kind is an unsigned integer one.
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// isFloat returns true if the given kind is a floating-point one.
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// isNumber returns true if the given kind is a numeric one.
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k)
+}
@@ -0,0 +1,128 @@
+package deepcopier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrCycle is returned with the CycleError policy when a source pointer is
+// met again while it is being copied.
+var ErrCycle = errors.New("cycle")
+
+// CyclePolicy defines how a source pointer met again while it is being copied
+// is copied.
+type CyclePolicy int
+
+const (
+	// CycleError returns ErrCycle.
+	CycleError CyclePolicy = iota
+	// CycleSkip leaves the destination unchanged.
+	CycleSkip
+	// CycleReuse sets the destination pointer already copied from the same
+	// source pointer, so that the destination graph has the shape of the
+	// source graph. Pointers shared by several fields are copied once.
+	// Destinations which are not pointers are left unchanged.
+	CycleReuse
+)
+
+// visitKey identifies the copy of a source pointer to a destination type.
+type visitKey struct {
+	ptr uintptr
+	src reflect.Type
+	dst reflect.Type
+}
+
+// visit is a source pointer being copied, linked to the copy of its parent.
+type visit struct {
+	key    visitKey
+	dst    reflect.Value
+	parent *visit
+}
+
+// copyCache records destination pointers copied from source pointers with
+// the CycleReuse policy.
+type copyCache struct {
+	mu     sync.Mutex
+	copies map[visitKey]reflect.Value
+}
+
+// newVisitKey returns the key of the given source copied to the given
+// destination pointer type. It returns false if the source is not a pointer.
+func newVisitKey(src reflect.Value, dst reflect.Type) (visitKey, bool) {
+	src = dynamicValue(src)
+	if src.Kind() != reflect.Ptr || src.IsNil() {
+		return visitKey{}, false
+	}
+	return visitKey{ptr: src.Pointer(), src: src.Type(), dst: dst}, true
+}
+
+// enter returns options for copying the given source into the given
+// destination pointer.
+func (o Options) enter(src reflect.Value, dst reflect.Value) Options {
+	key, ok := newVisitKey(src, dst.Type())
+	if !ok {
+		return o
+	}
+
+	o.visits = &visit{key: key, dst: dst, parent: o.visits}
+
+	if o.copies != nil {
+		o.copies.mu.Lock()
+		o.copies.copies[key] = dst
+		o.copies.mu.Unlock()
+	}
+
+	return o
+}
+
+// visited returns the destination pointer being copied from the given source,
+// or already copied with the CycleReuse policy.
+func (o Options) visited(src reflect.Value, dst reflect.Type) (reflect.Value, bool) {
+	key, ok := newVisitKey(src, dst)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	if o.copies != nil {
+		o.copies.mu.Lock()
+		defer o.copies.mu.Unlock()
+
+		v, ok := o.copies.copies[key]
+		return v, ok
+	}
+
+	for v := o.visits; v != nil; v = v.parent {
+		if v.key == key {
+			return v.dst, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// checkCycle applies the cycle policy if the given source is already being
+// copied to the given destination pointer type. It returns true if the
+// source must not be copied, with the destination pointer to reuse if any.
+func checkCycle(src reflect.Value, dst reflect.Type, options Options) (reflect.Value, bool, error) {
+	v, ok := options.visited(src, dst)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	switch options.Cycles {
+	case CycleSkip:
+		return reflect.Value{}, true, nil
+	case CycleReuse:
+		return v, true, nil
+	}
+
+	return reflect.Value{}, true, fmt.Errorf("%w of %s", ErrCycle, dynamicValue(src).Type())
+}
+
+// maxDepthReached returns true if structs nested in the one being copied
+// exceed the maximum depth.
+func (o Options) maxDepthReached() bool {
+	return o.MaxDepth > 0 && o.depth >= o.MaxDepth
+}
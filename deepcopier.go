@@ -1,6 +1,7 @@
 package deepcopier
 
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
@@ -18,6 +19,30 @@ const (
 	SkipOptionName = "skip"
 	// ForceOptionName is the skip option name for struct tag.
 	ForceOptionName = "force"
+	// SetterOptionName is the setter option name for struct tag.
+	SetterOptionName = "setter"
+	// DefaultOptionName is the default option name for struct tag.
+	DefaultOptionName = "default"
+	// FromContextOptionName is the fromcontext option name for struct tag.
+	FromContextOptionName = "fromcontext"
+	// ReadOnlyOptionName is the readonly option name for struct tag.
+	ReadOnlyOptionName = "readonly"
+	// WriteOnlyOptionName is the writeonly option name for struct tag.
+	WriteOnlyOptionName = "writeonly"
+	// ToOptionName is the to option name for struct tag.
+	ToOptionName = "to"
+	// FromOptionName is the from option name for struct tag.
+	FromOptionName = "from"
+	// LayoutOptionName is the time layout option name for struct tag.
+	LayoutOptionName = "layout"
+	// TimeZoneOptionName is the time zone option name for struct tag.
+	TimeZoneOptionName = "tz"
+	// MergeKeyOptionName is the merge key option name for struct tag.
+	MergeKeyOptionName = "mergekey"
+	// OrphanOptionName is the orphan option name for struct tag.
+	OrphanOptionName = "orphan"
+	// IfOptionName is the if option name for struct tag.
+	IfOptionName = "if"
 )
 
 type (
@@ -28,16 +53,49 @@ type (
 	Options struct {
 		// Context given to WithContext() method.
 		Context map[string]interface{}
+		// StdContext given to WithStdContext() method.
+		StdContext context.Context
 		// Reversed reverses struct tag checkings.
 		Reversed bool
+		// Strict returns an error on invalid struct tags.
+		Strict bool
+		// Defaults returns default values of zero destination fields.
+		Defaults DefaultFunc
+		// Parallel is the number of goroutines copying elements of slices
+		// and maps. Elements are copied sequentially if lower than 2.
+		Parallel int
+		// MaxDepth is the maximum number of levels of nested structs copied,
+		// the copied struct being the first one. Unlimited if zero.
+		MaxDepth int
+		// Cycles is the policy of source pointers met again while they are
+		// being copied.
+		Cycles CyclePolicy
+		// Observer is notified of the copy, instead of the observer set by
+		// SetObserver().
+		Observer Observer
+
+		// path is the path of the destination being copied.
+		path string
+		// changes records changes made to the destination.
+		changes *[]Change
+		// dryRun records changes without calling setters, hooks and
+		// validators, which could have side effects.
+		dryRun bool
+		// depth is the level of the struct being copied.
+		depth int
+		// visits are the source pointers being copied.
+		visits *visit
+		// copies are the pointers copied with the CycleReuse policy.
+		copies *copyCache
 	}
 )
 
 // DeepCopier deep copies a struct to/from a struct.
 type DeepCopier struct {
-	dst interface{}
-	src interface{}
-	ctx map[string]interface{}
+	dst       interface{}
+	src       interface{}
+	options   Options
+	validator ValidatorFunc
 }
 
 // Copy sets source or destination.
@@ -47,31 +105,109 @@ func Copy(src interface{}) *DeepCopier {
 
 // WithContext injects the given context into the builder instance.
 func (dc *DeepCopier) WithContext(ctx map[string]interface{}) *DeepCopier {
-	dc.ctx = ctx
+	dc.options.Context = ctx
+	return dc
+}
+
+// WithStdContext sets the context.Context looked up by fromcontext options
+// missing from the context given to WithContext() method.
+func (dc *DeepCopier) WithStdContext(ctx context.Context) *DeepCopier {
+	dc.options.StdContext = ctx
+	return dc
+}
+
+// WithValidator sets the function validating the destination after copy.
+func (dc *DeepCopier) WithValidator(validator ValidatorFunc) *DeepCopier {
+	dc.validator = validator
+	return dc
+}
+
+// WithChangeLog records changes made to the destination into the given slice.
+func (dc *DeepCopier) WithChangeLog(changes *[]Change) *DeepCopier {
+	dc.options.changes = changes
+	return dc
+}
+
+// WithDefaults sets the function returning default values of destination
+// fields left zero by the copy.
+func (dc *DeepCopier) WithDefaults(defaults DefaultFunc) *DeepCopier {
+	dc.options.Defaults = defaults
+	return dc
+}
+
+// Strict returns an error when struct tags are invalid.
+func (dc *DeepCopier) Strict() *DeepCopier {
+	dc.options.Strict = true
+	return dc
+}
+
+// Parallel copies elements of slices and maps in a pool of n goroutines.
+// Elements keep their order and nested collections are copied sequentially.
+func (dc *DeepCopier) Parallel(n int) *DeepCopier {
+	dc.options.Parallel = n
+	return dc
+}
+
+// MaxDepth copies at most n levels of nested structs, the copied struct being
+// the first one. Deeper structs are left unchanged.
+func (dc *DeepCopier) MaxDepth(n int) *DeepCopier {
+	dc.options.MaxDepth = n
+	return dc
+}
+
+// OnCycle sets the policy of source pointers met again while they are being
+// copied, CycleError by default.
+func (dc *DeepCopier) OnCycle(policy CyclePolicy) *DeepCopier {
+	dc.options.Cycles = policy
+	return dc
+}
+
+// WithObserver sets the observer notified of the copy.
+func (dc *DeepCopier) WithObserver(o Observer) *DeepCopier {
+	dc.options.Observer = o
 	return dc
 }
 
 // To sets the destination.
 func (dc *DeepCopier) To(dst interface{}) error {
 	dc.dst = dst
-	return process(dc.dst, dc.src, Options{Context: dc.ctx})
+	dc.options.Reversed = false
+	return dc.process()
 }
 
 // From sets the given the source as destination and destination as source.
 func (dc *DeepCopier) From(src interface{}) error {
 	dc.dst = dc.src
 	dc.src = src
-	return process(dc.dst, dc.src, Options{Context: dc.ctx, Reversed: true})
+	dc.options.Reversed = true
+	return dc.process()
+}
+
+// process copies the source into the destination then validates it.
+func (dc *DeepCopier) process() error {
+	return observe(dc.dst, dc.src, dc.options, func() error {
+		if err := process(dc.dst, dc.src, dc.options); err != nil {
+			return err
+		}
+
+		if dc.validator == nil {
+			return nil
+		}
+
+		if err := dc.validator(dc.dst); err != nil {
+			return &ValidationError{Err: err}
+		}
+
+		return nil
+	})
 }
 
 // process handles copy.
 func process(dst interface{}, src interface{}, args ...Options) error {
 	var (
-		options        = Options{}
-		srcValue       = reflect.Indirect(reflect.ValueOf(src))
-		dstValue       = reflect.Indirect(reflect.ValueOf(dst))
-		srcFieldNames  = getFieldNames(src)
-		srcMethodNames = getMethodNames(src)
+		options  = Options{}
+		srcValue = reflect.Indirect(reflect.ValueOf(src))
+		dstValue = reflect.Indirect(reflect.ValueOf(dst))
 	)
 
 	if len(args) > 0 {
@@ -82,218 +218,621 @@ func process(dst interface{}, src interface{}, args ...Options) error {
 		return fmt.Errorf("destination %+v is unaddressable", dstValue.Interface())
 	}
 
-	for _, f := range srcFieldNames {
-		var (
-			srcFieldValue               = srcValue.FieldByName(f)
-			srcFieldType, srcFieldFound = srcValue.Type().FieldByName(f)
-			srcFieldName                = srcFieldType.Name
-			dstFieldName                = srcFieldName
-			tagOptions                  TagOptions
-		)
-
-		if !srcFieldFound {
-			continue
+	if options.Strict {
+		if err := checkTags(srcValue.Type()); err != nil {
+			return err
 		}
 
-		if options.Reversed {
-			tagOptions = getTagOptions(srcFieldType.Tag.Get(TagName))
-			if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
-				dstFieldName = v
-			}
-		} else {
-			if name, opts := getRelatedField(dst, srcFieldName); name != "" {
-				dstFieldName, tagOptions = name, opts
-			}
+		if err := checkTags(dstValue.Type()); err != nil {
+			return err
 		}
+	}
 
-		if _, ok := tagOptions[SkipOptionName]; ok {
-			continue
+	plan := getCompiledPlan(srcValue.Type(), dstValue.Type(), options.Reversed)
+	if plan.err != nil {
+		return plan.err
+	}
+
+	if !options.dryRun {
+		if err := beforeCopy(dst, src, options); err != nil {
+			return err
 		}
+	}
 
-		var (
-			dstFieldType, dstFieldFound = dstValue.Type().FieldByName(dstFieldName)
-			dstFieldValue               = dstValue.FieldByName(dstFieldName)
-		)
+	var err error
 
-		if !dstFieldFound {
-			continue
+	if options.Cycles == CycleReuse && options.copies == nil {
+		options.copies = &copyCache{copies: map[visitKey]reflect.Value{}}
+	}
+
+	// Structs copied from maps are nested levels too
+	if srcValue.Kind() == reflect.Struct || dstValue.Kind() == reflect.Struct {
+		options.depth++
+	}
+
+	if srcValue.Kind() == reflect.Struct && (!plan.flat || options.copies != nil) {
+		options = options.enter(reflect.ValueOf(src), reflect.ValueOf(dst))
+	}
+
+	switch {
+	// Struct -> Map
+	case srcValue.Kind() == reflect.Struct && dstValue.Kind() == reflect.Map:
+		err = copyStructToMap(dstValue, srcValue, options)
+	// Map -> Struct
+	case srcValue.Kind() == reflect.Map && dstValue.Kind() == reflect.Struct:
+		err = copyMapToStruct(dstValue, srcValue, options)
+	// Slice -> Slice, Map -> Map
+	case isCollection(srcValue.Type(), dstValue.Type()):
+		var v reflect.Value
+		if v, err = copyCollection(dstValue.Type(), srcValue, options); err == nil {
+			setField(dstValue, "", options, v)
 		}
+	default:
+		err = copyStruct(dst, src, plan, options)
+	}
 
-		// Force option for empty interfaces and nullable types
-		_, force := tagOptions[ForceOptionName]
+	if err != nil {
+		return err
+	}
 
-		// Valuer -> ptr
-		if isNullableType(srcFieldType.Type) && dstFieldValue.Kind() == reflect.Ptr && force {
-			// We have same nullable type on both sides
-			if srcFieldValue.Type().AssignableTo(dstFieldType.Type) {
-				dstFieldValue.Set(srcFieldValue)
-				continue
+	if dstValue.Kind() == reflect.Struct {
+		if srcValue.Kind() == reflect.Struct {
+			if err := afterCopyExtensions(dstValue, srcValue, options); err != nil {
+				return err
 			}
+		}
 
-			v, _ := srcFieldValue.Interface().(driver.Valuer).Value()
-			if v == nil {
-				continue
-			}
+		if err := applyFromContext(dstValue, plan, options); err != nil {
+			return err
+		}
 
-			valueType := reflect.TypeOf(v)
+		if err := applyDefaults(dstValue, plan, options); err != nil {
+			return err
+		}
+	}
 
-			ptr := reflect.New(valueType)
-			ptr.Elem().Set(reflect.ValueOf(v))
+	if options.dryRun {
+		return nil
+	}
 
-			if valueType.AssignableTo(dstFieldType.Type.Elem()) {
-				dstFieldValue.Set(ptr)
-			}
+	if err := afterCopy(dst, src, options); err != nil {
+		return err
+	}
 
-			continue
+	return validate(dst)
+}
+
+// copyStruct copies source fields and methods into destination fields,
+// following the given compiled plan.
+func copyStruct(dst interface{}, src interface{}, plan *compiledPlan, options Options) error {
+	var (
+		srcValue = reflect.Indirect(reflect.ValueOf(src))
+		dstValue = reflect.Indirect(reflect.ValueOf(dst))
+		receiver = getMethodReceiver(src)
+		observer = options.observer()
+	)
+
+	for _, f := range plan.fields {
+		// Fields are copied only if their condition holds
+		copied, err := f.cond.holds(dstValue, receiver, f.dst, options)
+		if err != nil {
+			return newFieldError(f.dst, err)
 		}
 
-		// Valuer -> value
-		if isNullableType(srcFieldType.Type) {
-			// We have same nullable type on both sides
-			if srcFieldValue.Type().AssignableTo(dstFieldType.Type) {
-				dstFieldValue.Set(srcFieldValue)
-				continue
+		if copied {
+			if copied, err = copyField(dstValue, srcValue, f, plan.profile, options); err != nil {
+				return err
 			}
+		}
 
-			if force {
-				v, _ := srcFieldValue.Interface().(driver.Valuer).Value()
-				if v == nil {
-					continue
-				}
+		if observer != nil {
+			observer.OnField(joinPath(options.path, f.dst), copied)
+		}
+	}
+
+	for _, m := range plan.methods {
+		copied, err := m.cond.holds(dstValue, receiver, m.dst, options)
+		if err != nil {
+			return newFieldError(m.dst, err)
+		}
 
-				rv := reflect.ValueOf(v)
-				if rv.Type().AssignableTo(dstFieldType.Type) {
-					dstFieldValue.Set(rv)
-				}
+		if copied {
+			if copied, err = copyMethod(dstValue, receiver, m, plan.profile, options); err != nil {
+				return err
 			}
+		}
 
-			continue
+		if observer != nil {
+			observer.OnField(joinPath(options.path, m.dst), copied)
 		}
+	}
 
-		if dstFieldValue.Kind() == reflect.Interface {
-			if force {
-				dstFieldValue.Set(srcFieldValue)
-			}
-			continue
+	return plan.profile.applyResolvers(dstValue, srcValue, options)
+}
+
+// copyField copies the source field of the given plan into its destination
+// field. It returns false if the field is not copied.
+func copyField(dstValue reflect.Value, srcValue reflect.Value, f fieldPlan, profile *profile, options Options) (bool, error) {
+	var (
+		srcFieldValue = srcValue.FieldByIndex(f.src.Index)
+		srcFieldType  = f.src
+		dstFieldName  = f.dst
+		tagOptions    = f.options
+	)
+
+	// Same basic types
+	if f.assign {
+		setField(dstValue.FieldByIndex(f.dstField.Index), dstFieldName, options, srcFieldValue)
+		return true, nil
+	}
+
+	var (
+		dstFieldType  = f.dstField
+		dstFieldFound = f.dstFound
+		dstFieldValue reflect.Value
+	)
+
+	if dstFieldFound {
+		dstFieldValue = dstValue.FieldByIndex(dstFieldType.Index)
+	}
+
+	// Profile converters
+	if fn := profile.converter(dstFieldName); fn != nil && dstFieldFound {
+		if err := convertValue(dstFieldValue, dstFieldName, fn, srcFieldValue, options); err != nil {
+			return false, newFieldError(dstFieldName, err)
 		}
+		return true, nil
+	}
 
-		// Ptr -> Value
-		if srcFieldType.Type.Kind() == reflect.Ptr && !srcFieldValue.IsNil() && dstFieldType.Type.Kind() != reflect.Ptr {
-			indirect := reflect.Indirect(srcFieldValue)
+	// Setter methods for explicit setter option or missing/unexported field
+	if _, ok := tagOptions[SetterOptionName]; ok || !dstFieldFound || dstFieldType.PkgPath != "" {
+		called, err := callSetter(dstValue.Addr(), dstFieldName, tagOptions, srcFieldValue, options)
+		if err != nil {
+			return false, newFieldError(dstFieldName, err)
+		}
+		return called, nil
+	}
 
-			if indirect.Type().AssignableTo(dstFieldType.Type) {
-				dstFieldValue.Set(indirect)
-				continue
+	// Extension conversions
+	if v, ok := convertExtension(srcFieldValue, dstFieldType.Type); ok {
+		setField(dstFieldValue, dstFieldName, options, v)
+		return true, nil
+	}
+
+	// Force option for empty interfaces and nullable types
+	_, force := tagOptions[ForceOptionName]
+
+	// Time conversions
+	if isTimeConversion(srcFieldType.Type, dstFieldType.Type) && (force || !isNullableType(srcFieldType.Type)) {
+		v, ok, err := convertTime(srcFieldValue, dstFieldType.Type, tagOptions, f.tz, options)
+		if err != nil {
+			if options.Strict {
+				return false, newFieldError(dstFieldName, err)
 			}
+			return false, nil
 		}
 
-		// Other types
-		if srcFieldType.Type.AssignableTo(dstFieldType.Type) {
-			dstFieldValue.Set(srcFieldValue)
+		if !ok {
+			return false, nil
 		}
+
+		setField(dstFieldValue, dstFieldName, options, v)
+		return true, nil
 	}
 
-	for _, m := range srcMethodNames {
-		name, opts := getRelatedField(dst, m)
-		if name == "" {
-			continue
+	// Valuer -> ptr
+	if isNullableType(srcFieldType.Type) && dstFieldValue.Kind() == reflect.Ptr && force {
+		// We have same nullable type on both sides
+		if srcFieldValue.Type().AssignableTo(dstFieldType.Type) {
+			setField(dstFieldValue, dstFieldName, options, srcFieldValue)
+			return true, nil
 		}
 
-		if _, ok := opts[SkipOptionName]; ok {
-			continue
+		v, _ := srcFieldValue.Interface().(driver.Valuer).Value()
+		if v == nil {
+			return false, nil
 		}
 
-		method := reflect.ValueOf(src).MethodByName(m)
-		if !method.IsValid() {
-			return fmt.Errorf("method %s is invalid", m)
+		valueType := reflect.TypeOf(v)
+
+		ptr := reflect.New(valueType)
+		ptr.Elem().Set(reflect.ValueOf(v))
+
+		if !valueType.AssignableTo(dstFieldType.Type.Elem()) {
+			return false, nil
 		}
 
-		var (
-			dstFieldType, _ = dstValue.Type().FieldByName(name)
-			dstFieldValue   = dstValue.FieldByName(name)
-			_, withContext  = opts[ContextOptionName]
-			_, force        = opts[ForceOptionName]
-		)
+		setField(dstFieldValue, dstFieldName, options, ptr)
+		return true, nil
+	}
 
-		args := []reflect.Value{}
-		if withContext {
-			args = []reflect.Value{reflect.ValueOf(options.Context)}
+	// Valuer -> value
+	if isNullableType(srcFieldType.Type) {
+		// We have same nullable type on both sides
+		if srcFieldValue.Type().AssignableTo(dstFieldType.Type) {
+			setField(dstFieldValue, dstFieldName, options, srcFieldValue)
+			return true, nil
 		}
 
-		var (
-			result          = method.Call(args)[0]
-			resultInterface = result.Interface()
-			resultValue     = reflect.ValueOf(resultInterface)
-			resultType      = resultValue.Type()
-		)
+		if !force {
+			return false, nil
+		}
 
-		// Value -> Ptr
-		if dstFieldValue.Kind() == reflect.Ptr && force {
-			ptr := reflect.New(resultType)
-			ptr.Elem().Set(resultValue)
+		v, _ := srcFieldValue.Interface().(driver.Valuer).Value()
+		if v == nil {
+			return false, nil
+		}
 
-			if ptr.Type().AssignableTo(dstFieldType.Type) {
-				dstFieldValue.Set(ptr)
-			}
+		rv := reflect.ValueOf(v)
+		if !rv.Type().AssignableTo(dstFieldType.Type) {
+			return false, nil
+		}
 
-			continue
+		setField(dstFieldValue, dstFieldName, options, rv)
+		return true, nil
+	}
+
+	// Registered targets and forced interfaces
+	if dstFieldValue.Kind() == reflect.Interface {
+		copied, err := copyInterface(dstFieldValue, dstFieldName, srcFieldValue, force, options)
+		if err != nil {
+			return false, newFieldError(dstFieldName, err)
+		}
+		return copied, nil
+	}
+
+	// Interface -> concrete type
+	if srcFieldType.Type.Kind() == reflect.Interface {
+		copied, err := copyDynamic(dstFieldValue, dstFieldName, srcFieldValue, options)
+		if err != nil {
+			return false, newFieldError(dstFieldName, err)
+		}
+		return copied, nil
+	}
+
+	// Slices of structs merged by key
+	if isMerge(srcFieldType.Type, dstFieldType.Type, tagOptions) {
+		if srcFieldValue.IsNil() || options.maxDepthReached() {
+			return false, nil
+		}
+
+		if err := mergeCollection(dstFieldValue, dstFieldName, srcFieldValue, tagOptions, options); err != nil {
+			return false, newFieldError(dstFieldName, err)
+		}
+		return true, nil
+	}
+
+	// Ptr -> Value
+	if srcFieldType.Type.Kind() == reflect.Ptr && !srcFieldValue.IsNil() && dstFieldType.Type.Kind() != reflect.Ptr {
+		indirect := reflect.Indirect(srcFieldValue)
+
+		if indirect.Type().AssignableTo(dstFieldType.Type) {
+			setField(dstFieldValue, dstFieldName, options, indirect)
+			return true, nil
 		}
+	}
 
-		// Ptr -> value
-		if resultValue.Kind() == reflect.Ptr && force {
-			if resultValue.Elem().Type().AssignableTo(dstFieldType.Type) {
-				dstFieldValue.Set(resultValue.Elem())
+	// Other types
+	if srcFieldType.Type.AssignableTo(dstFieldType.Type) {
+		setField(dstFieldValue, dstFieldName, options, srcFieldValue)
+		return true, nil
+	}
+
+	// Slices and maps of structs
+	if isCollection(srcFieldType.Type, dstFieldType.Type) {
+		if options.maxDepthReached() {
+			return false, nil
+		}
+
+		v, err := copyCollection(dstFieldType.Type, srcFieldValue, options.withPath(dstFieldName))
+		if err != nil {
+			return false, newFieldError(dstFieldName, err)
+		}
+
+		setField(dstFieldValue, dstFieldName, options, v)
+		return true, nil
+	}
+
+	// Nested structs and maps
+	copied, err := copyNested(dstFieldValue, srcFieldValue, options.withPath(dstFieldName))
+	if err != nil {
+		return false, newFieldError(dstFieldName, err)
+	}
+
+	return copied, nil
+}
+
+// copyMethod copies the result of the source method of the given plan into
+// its destination field. It returns false if the field is not copied.
+func copyMethod(dstValue reflect.Value, receiver reflect.Value, m methodPlan, profile *profile, options Options) (bool, error) {
+	var (
+		name = m.dst
+		opts = m.options
+	)
+
+	// Methods promoted through nil embedded pointers cannot be called
+	if hasNilEmbedded(receiver.Elem(), m.embedded) {
+		return false, nil
+	}
+
+	method := receiver.Method(m.index)
+	if !method.IsValid() {
+		return false, fmt.Errorf("method %s is invalid", m.name)
+	}
+
+	var (
+		dstFieldType   = m.dstField
+		dstFieldValue  = dstValue.FieldByIndex(dstFieldType.Index)
+		_, withContext = opts[ContextOptionName]
+		_, force       = opts[ForceOptionName]
+	)
+
+	var args []reflect.Value
+	if withContext && method.Type().NumIn() == 1 {
+		ctx := newContext(dstValue.Addr().Interface(), receiver.Interface(), joinPath(options.path, name), options)
+		args = []reflect.Value{contextArg(method.Type().In(0), ctx)}
+	}
+
+	var (
+		result      = method.Call(args)[0]
+		resultValue = result
+	)
+
+	if result.Kind() == reflect.Interface {
+		resultValue = result.Elem()
+	}
+
+	resultType := resultValue.Type()
+
+	// Profile converters
+	if fn := profile.converter(name); fn != nil {
+		if err := convertValue(dstFieldValue, name, fn, result, options); err != nil {
+			return false, newFieldError(name, err)
+		}
+		return true, nil
+	}
+
+	// Time conversions
+	if isTimeConversion(resultType, dstFieldType.Type) && (force || !isNullableType(resultType)) {
+		v, ok, err := convertTime(resultValue, dstFieldType.Type, opts, m.tz, options)
+		if err != nil {
+			if options.Strict {
+				return false, newFieldError(name, err)
 			}
+			return false, nil
+		}
 
-			continue
+		if !ok {
+			return false, nil
+		}
+
+		setField(dstFieldValue, name, options, v)
+		return true, nil
+	}
+
+	// Value -> Ptr
+	if dstFieldValue.Kind() == reflect.Ptr && force {
+		ptr := reflect.New(resultType)
+		ptr.Elem().Set(resultValue)
+
+		if !ptr.Type().AssignableTo(dstFieldType.Type) {
+			return false, nil
 		}
 
-		if resultType.AssignableTo(dstFieldType.Type) && result.IsValid() {
-			dstFieldValue.Set(result)
+		setField(dstFieldValue, name, options, ptr)
+		return true, nil
+	}
+
+	// Ptr -> value
+	if resultValue.Kind() == reflect.Ptr && force {
+		if !resultValue.Elem().Type().AssignableTo(dstFieldType.Type) {
+			return false, nil
 		}
+
+		setField(dstFieldValue, name, options, resultValue.Elem())
+		return true, nil
+	}
+
+	if !resultType.AssignableTo(dstFieldType.Type) || !result.IsValid() {
+		return false, nil
 	}
 
-	return nil
+	setField(dstFieldValue, name, options, result)
+	return true, nil
 }
 
-// getTagOptions parses deepcopier tag field and returns options.
-func getTagOptions(value string) TagOptions {
-	options := TagOptions{}
+// copyNested copies the given struct or map value into the given struct or map
+// field. It returns false if one of both values cannot be copied field by
+// field.
+func copyNested(dst reflect.Value, src reflect.Value, options Options) (bool, error) {
+	if !isNestedStruct(src) && !(isStringMap(src.Type()) && !src.IsNil()) {
+		return false, nil
+	}
+
+	t := dst.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct && !isStringMap(t) {
+		return false, nil
+	}
+
+	if isStringMap(src.Type()) && isStringMap(t) {
+		return false, nil
+	}
+
+	if options.maxDepthReached() {
+		return false, nil
+	}
+
+	v, found, err := checkCycle(src, reflect.PtrTo(t), options)
+	if err != nil {
+		return false, err
+	}
+
+	if found {
+		if !v.IsValid() || dst.Kind() != reflect.Ptr {
+			return false, nil
+		}
+
+		dst.Set(v)
+		return true, nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(t))
+		}
+		return true, process(dst.Interface(), src.Interface(), options)
+	}
+
+	return true, process(dst.Addr().Interface(), src.Interface(), options)
+}
 
-	for _, opt := range strings.Split(value, ";") {
-		o := strings.Split(opt, ":")
+// setterName returns the name of the default setter method of the given
+// field: "Set" followed by the field name.
+func setterName(fieldName string) string {
+	return "Set" + strings.ToUpper(fieldName[:1]) + fieldName[1:]
+}
 
-		// deepcopier:"keyword; without; value;"
-		if len(o) == 1 {
-			options[o[0]] = ""
+// callSetter calls the setter method of the given destination with the given
+// value converted to the setter argument type. The method is the one defined
+// by the setter option or "Set" followed by the field name. It returns false
+// if the setter is not called.
+func callSetter(dst reflect.Value, fieldName string, tagOptions TagOptions, value reflect.Value, options Options) (bool, error) {
+	name, explicit := tagOptions[SetterOptionName], true
+	if name == "" {
+		name, explicit = setterName(fieldName), false
+	}
+
+	method := dst.MethodByName(name)
+	if !method.IsValid() {
+		if explicit {
+			return false, fmt.Errorf("method %s is invalid", name)
 		}
+		return false, nil
+	}
 
-		// deepcopier:"key:value; anotherkey:anothervalue"
-		if len(o) == 2 {
-			options[strings.TrimSpace(o[0])] = strings.TrimSpace(o[1])
+	t := method.Type()
+	if t.NumIn() != 1 || t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		if explicit {
+			return false, fmt.Errorf("method %s must take one argument and return nothing or an error", name)
 		}
+		return false, nil
+	}
+
+	arg, ok := convert(value, t.In(0))
+	if !ok {
+		return false, nil
+	}
+
+	// Setters are only recorded by dry runs
+	if options.dryRun {
+		options.record(fieldName, nil, arg.Interface())
+		return true, nil
 	}
 
+	out := method.Call([]reflect.Value{arg})
+	if len(out) == 1 && !out[0].IsNil() {
+		return false, out[0].Interface().(error)
+	}
+
+	options.record(fieldName, nil, arg.Interface())
+
+	return true, nil
+}
+
+// getTagOptions parses deepcopier tag field and returns options, ignoring
+// parsing errors.
+func getTagOptions(value string) TagOptions {
+	options, _ := ParseTag(value)
 	return options
 }
 
-// getRelatedField returns first matching field.
-func getRelatedField(instance interface{}, name string) (string, TagOptions) {
+// direction returns the options used in the given copy direction: the to (or
+// from, when reversed) option replaces the field option, and writeonly (or
+// readonly, when reversed) fields are skipped.
+func (o TagOptions) direction(reversed bool) TagOptions {
+	name, skip := ToOptionName, WriteOnlyOptionName
+	if reversed {
+		name, skip = FromOptionName, ReadOnlyOptionName
+	}
+
+	var (
+		_, rename  = o[name]
+		_, skipped = o[skip]
+	)
+
+	if !rename && !skipped {
+		return o
+	}
+
+	options := make(TagOptions, len(o)+1)
+	for k, v := range o {
+		options[k] = v
+	}
+
+	if rename {
+		options[FieldOptionName] = o[name]
+	}
+
+	if skipped {
+		options[SkipOptionName] = ""
+	}
+
+	return options
+}
+
+// getDestinationOptions returns struct tag options of destination fields by
+// field name. Options are declared on the destination or, when reversed, on
+// the source fields, the field option giving the destination field name.
+func getDestinationOptions(dst reflect.Type, src reflect.Type, reversed bool) map[string]TagOptions {
+	var (
+		options    = map[string]TagOptions{}
+		tagged     = indirectType(dst)
+		fromSource = reversed && indirectType(src).Kind() == reflect.Struct
+	)
+
+	if fromSource {
+		tagged = indirectType(src)
+	}
+
+	for _, f := range getTypeFieldNames(tagged) {
+		var (
+			field, _   = tagged.FieldByName(f)
+			tagOptions = getTagOptions(field.Tag.Get(TagName)).direction(reversed)
+			name       = f
+		)
+
+		if v := tagOptions[FieldOptionName]; v != "" && fromSource {
+			name = v
+		}
+
+		options[name] = tagOptions
+	}
+
+	return options
+}
+
+// getTypeRelatedField returns first matching field of the given type in the
+// given copy direction.
+func getTypeRelatedField(t reflect.Type, name string, reversed bool) (string, TagOptions) {
 	var (
-		value      = reflect.Indirect(reflect.ValueOf(instance))
 		fieldName  string
 		tagOptions TagOptions
 	)
 
-	for i := 0; i < value.NumField(); i++ {
+	t = indirectType(t)
+
+	for i := 0; i < t.NumField(); i++ {
 		var (
-			vField     = value.Field(i)
-			tField     = value.Type().Field(i)
-			tagOptions = getTagOptions(tField.Tag.Get(TagName))
+			tField     = t.Field(i)
+			tagOptions = getTagOptions(tField.Tag.Get(TagName)).direction(reversed)
 		)
 
 		if tField.Type.Kind() == reflect.Struct && tField.Anonymous {
-			if n, o := getRelatedField(vField.Interface(), name); n != "" {
+			if n, o := getTypeRelatedField(tField.Type, name, reversed); n != "" {
 				return n, o
 			}
 		}
@@ -310,11 +849,12 @@ func getRelatedField(instance interface{}, name string) (string, TagOptions) {
 	return fieldName, tagOptions
 }
 
-// getMethodNames returns instance's method names.
-func getMethodNames(instance interface{}) []string {
+// getTypeMethodNames returns method names of the given type, including
+// methods with pointer receivers.
+func getTypeMethodNames(t reflect.Type) []string {
 	var methods []string
 
-	t := reflect.TypeOf(instance)
+	t = reflect.PtrTo(indirectType(t))
 	for i := 0; i < t.NumMethod(); i++ {
 		methods = append(methods, t.Method(i).Name)
 	}
@@ -322,31 +862,49 @@ func getMethodNames(instance interface{}) []string {
 	return methods
 }
 
+// getMethodReceiver returns a pointer to the given instance so that methods
+// with both value and pointer receivers can be called. Values are copied
+// into a new addressable instance.
+func getMethodReceiver(instance interface{}) reflect.Value {
+	v := reflect.ValueOf(instance)
+	if v.Kind() == reflect.Ptr {
+		return v
+	}
+
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+
+	return ptr
+}
+
 // getFieldNames returns instance's field names.
 func getFieldNames(instance interface{}) []string {
-	var (
-		fields []string
-		v      = reflect.Indirect(reflect.ValueOf(instance))
-		t      = v.Type()
-	)
+	return getTypeFieldNames(reflect.TypeOf(instance))
+}
 
+// getTypeFieldNames returns field names of the given type.
+func getTypeFieldNames(t reflect.Type) []string {
+	var fields []string
+
+	t = indirectType(t)
 	if t.Kind() != reflect.Struct {
 		return nil
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		var (
-			vField = v.Field(i)
-			tField = v.Type().Field(i)
-		)
+	for i := 0; i < t.NumField(); i++ {
+		tField := t.Field(i)
 
 		// Is exportable?
 		if tField.PkgPath != "" {
 			continue
 		}
 
+		if skipExtensionField(t, tField) {
+			continue
+		}
+
 		if tField.Type.Kind() == reflect.Struct && tField.Anonymous {
-			fields = append(fields, getFieldNames(vField.Interface())...)
+			fields = append(fields, getTypeFieldNames(tField.Type)...)
 			continue
 		}
 
@@ -356,6 +914,17 @@ func getFieldNames(instance interface{}) []string {
 	return fields
 }
 
+// indirectType returns the element type of the given pointer type.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// errorType is the error interface type.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // isNullableType returns true if the given type is a nullable one.
 func isNullableType(t reflect.Type) bool {
 	return t.ConvertibleTo(reflect.TypeOf((*driver.Valuer)(nil)).Elem())
@@ -0,0 +1,211 @@
+package deepcopier
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// DefaultFunc returns the default value of the given destination field, used
+// when the field is zero after copy. Strings are parsed like default options.
+type DefaultFunc func(dst reflect.Type, field reflect.StructField) (interface{}, bool)
+
+// applyDefaults sets default values to zero destination fields, from default
+// options of struct tags or from the default function.
+func applyDefaults(dst reflect.Value, plan *compiledPlan, options Options) error {
+	defaults := plan.defaults
+
+	if len(defaults) == 0 && options.Defaults == nil {
+		return nil
+	}
+
+	for _, f := range getTypeFieldNames(dst.Type()) {
+		var (
+			field     = dst.FieldByName(f)
+			fieldType = field.Type()
+		)
+
+		if !field.IsZero() {
+			continue
+		}
+
+		if value, ok := defaults[f]; ok {
+			if !value.value.IsValid() {
+				continue
+			}
+
+			setField(field, f, options, copyDefault(value.value))
+			continue
+		}
+
+		if options.Defaults == nil {
+			continue
+		}
+
+		structField, _ := dst.Type().FieldByName(f)
+
+		value, ok := options.Defaults(dst.Type(), structField)
+		if !ok || value == nil {
+			continue
+		}
+
+		v, ok := convert(reflect.ValueOf(value), fieldType)
+		if !ok {
+			s, isString := value.(string)
+			if !isString {
+				return newFieldError(f, fmt.Errorf("cannot use default %v as %s", value, fieldType))
+			}
+
+			var err error
+			if v, err = parseDefault(s, fieldType); err != nil {
+				return newFieldError(f, err)
+			}
+		}
+
+		setField(field, f, options, v)
+	}
+
+	return nil
+}
+
+// defaultValue is the default option of a destination field.
+type defaultValue struct {
+	// raw is the option value.
+	raw string
+	// value is the option value parsed to the type of the field, invalid if
+	// it cannot be parsed.
+	value reflect.Value
+}
+
+// compileDefaults returns default options of destination fields, parsed to the
+// types of the fields, with the first invalid default option.
+func compileDefaults(dst reflect.Type, src reflect.Type, reversed bool) (map[string]defaultValue, error) {
+	var (
+		defaults   = map[string]defaultValue{}
+		tagged     = indirectType(dst)
+		fromSource = reversed && indirectType(src).Kind() == reflect.Struct
+		first      error
+	)
+
+	if indirectType(dst).Kind() != reflect.Struct {
+		return defaults, nil
+	}
+
+	if fromSource {
+		tagged = indirectType(src)
+	}
+
+	for _, f := range getTypeFieldNames(tagged) {
+		var (
+			field, _   = tagged.FieldByName(f)
+			tag        = field.Tag.Get(TagName)
+			tagOptions = getTagOptions(tag).direction(reversed)
+			name       = f
+		)
+
+		value, ok := tagOptions[DefaultOptionName]
+		if !ok {
+			continue
+		}
+
+		if v := tagOptions[FieldOptionName]; v != "" && fromSource {
+			name = v
+		}
+
+		dstField, ok := indirectType(dst).FieldByName(name)
+		if !ok {
+			continue
+		}
+
+		v, err := parseDefault(value, dstField.Type)
+		if err != nil && first == nil {
+			first = &TagError{Type: tagged, Field: f, Tag: tag, Err: err}
+		}
+
+		defaults[name] = defaultValue{raw: value, value: v}
+	}
+
+	return defaults, first
+}
+
+// copyDefault returns the given parsed default value, with new pointers so that
+// destinations never share them.
+func copyDefault(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return v
+	}
+
+	p := reflect.New(v.Type().Elem())
+	p.Elem().Set(copyDefault(v.Elem()))
+
+	return p
+}
+
+// parseDefault parses the given default value to the given type.
+func parseDefault(value string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+
+	switch {
+	case t.Kind() == reflect.Ptr:
+		elem, err := parseDefault(value, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(elem)
+	case t == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default duration %q", value)
+		}
+		v.SetInt(int64(d))
+	case t == timeType:
+		tm, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default time %q", value)
+		}
+		v.Set(reflect.ValueOf(tm))
+	case t.Kind() == reflect.String:
+		v.SetString(value)
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default bool %q", value)
+		}
+		v.SetBool(b)
+	case isInt(t.Kind()):
+		n, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default integer %q", value)
+		}
+		v.SetInt(n)
+	case isUint(t.Kind()):
+		n, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default unsigned integer %q", value)
+		}
+		v.SetUint(n)
+	case isFloat(t.Kind()):
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid default float %q", value)
+		}
+		v.SetFloat(f)
+	default:
+		// sql.Null* and other scanners
+		converted, ok := convert(reflect.ValueOf(value), t)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unsupported default for type %s", t)
+		}
+		return converted, nil
+	}
+
+	return v, nil
+}
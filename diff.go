@@ -0,0 +1,168 @@
+package deepcopier
+
+import (
+	"reflect"
+)
+
+// Change is a change made to a destination field by a copy.
+type Change struct {
+	// Path is the dot-separated path of the destination field.
+	Path string
+	// Old is the value of the field before copy (nil for setters and new map keys).
+	Old interface{}
+	// New is the value of the field after copy.
+	New interface{}
+}
+
+// Diff returns changes a copy of the given source to the given destination
+// would make. The copy runs on a deep copy of the destination, without
+// calling setters (recorded as changes), lifecycle hooks and validators.
+// Unexported fields of the deep copy are shared with the destination, and
+// source methods copied to fields or used by conditions are called.
+func Diff(src interface{}, dst interface{}) ([]Change, error) {
+	var changes []Change
+
+	dc := Copy(src).WithChangeLog(&changes)
+	dc.options.dryRun = true
+
+	if err := dc.To(clone(dst)); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// setField sets the given value to the given field, recording the change.
+func setField(field reflect.Value, name string, options Options, value reflect.Value) {
+	if options.changes == nil {
+		field.Set(value)
+		return
+	}
+
+	old := field.Interface()
+	field.Set(value)
+	options.record(name, old, field.Interface())
+}
+
+// record records a change of the given field if values differ.
+func (o Options) record(name string, old interface{}, new interface{}) {
+	if o.changes == nil || reflect.DeepEqual(old, new) {
+		return
+	}
+
+	*o.changes = append(*o.changes, Change{Path: joinPath(o.path, name), Old: old, New: new})
+}
+
+// withPath returns options for copying the given nested field.
+func (o Options) withPath(name string) Options {
+	o.path = joinPath(o.path, name)
+	return o
+}
+
+// withoutChanges returns options that do not record changes, for copies
+// into newly created values.
+func (o Options) withoutChanges() Options {
+	o.changes = nil
+	return o
+}
+
+// joinPath joins the given path and field name.
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	if name == "" {
+		return path
+	}
+	return path + "." + name
+}
+
+// clone returns a pointer to a deep copy of the given instance.
+func clone(instance interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(instance))
+
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(cloneValue(v, map[cloneKey]reflect.Value{}))
+
+	return ptr.Interface()
+}
+
+// cloneKey identifies a pointer or a map already cloned.
+type cloneKey struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+// cloneValue returns a deep copy of the given value. Pointers and maps met
+// again are copied once, so that cycles are kept. Unexported fields are
+// copied shallowly.
+func cloneValue(v reflect.Value, clones map[cloneKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		key := cloneKey{ptr: v.Pointer(), t: v.Type()}
+		if c, ok := clones[key]; ok {
+			return c
+		}
+
+		ptr := reflect.New(v.Type().Elem())
+		clones[key] = ptr
+		ptr.Elem().Set(cloneValue(v.Elem(), clones))
+
+		return ptr
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem(), clones))
+
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(cloneValue(v.Field(i), clones))
+			}
+		}
+
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(cloneValue(v.Index(i), clones))
+		}
+
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		key := cloneKey{ptr: v.Pointer(), t: v.Type()}
+		if c, ok := clones[key]; ok {
+			return c
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		clones[key] = c
+
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), cloneValue(iter.Value(), clones))
+		}
+
+		return c
+	}
+
+	return v
+}
@@ -0,0 +1,64 @@
+package deepcopier
+
+import "strings"
+
+// FieldError is an error that occurred while copying a destination field.
+type FieldError struct {
+	// Path is the dot-separated path of the destination field.
+	Path string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the destination is invalid after copy.
+type ValidationError struct {
+	// Err is the error returned by the validator.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newFieldError returns the given error as an error of the given field,
+// prefixing the path of nested field errors.
+func newFieldError(name string, err error) error {
+	if e, ok := err.(*FieldError); ok {
+		return &FieldError{Path: name + "." + e.Path, Err: e.Err}
+	}
+	return &FieldError{Path: name, Err: err}
+}
+
+// Errors are errors of several elements of a collection, returned in strict
+// mode instead of the first one.
+type Errors []error
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
@@ -1,6 +1,6 @@
 module github.com/ulule/deepcopier/examples
 
-go 1.14
+go 1.23
 
 require (
 	github.com/ant0ine/go-json-rest v3.3.2+incompatible
@@ -9,4 +9,20 @@ require (
 	github.com/ulule/deepcopier v0.0.0
 )
 
+require (
+	github.com/denisenkom/go-mssqldb v0.0.0-20191124224453-732737034ffd // indirect
+	github.com/erikstmartin/go-testdb v0.0.0-20160219214506-8d10e4a1bae5 // indirect
+	github.com/go-sql-driver/mysql v1.4.1 // indirect
+	github.com/golang-sql/civil v0.0.0-20190719163853-cb61b32ac6fe // indirect
+	github.com/golang/protobuf v1.2.0 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.0.1 // indirect
+	github.com/mattn/go-sqlite3 v2.0.1+incompatible // indirect
+	golang.org/x/crypto v0.0.0-20191205180655-e7c4368fe9dd // indirect
+	golang.org/x/net v0.0.0-20190404232315-eb5bcb51f2a3 // indirect
+	golang.org/x/sys v0.0.0-20190412213103-97732733099d // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/appengine v1.4.0 // indirect
+)
+
 replace github.com/ulule/deepcopier => ../
@@ -0,0 +1,507 @@
+package deepcopier
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Conversion describes how a source value is copied to a destination field.
+type Conversion string
+
+const (
+	// ConversionAssign assigns the value as is.
+	ConversionAssign Conversion = "assign"
+	// ConversionDereference assigns the value a pointer points to.
+	ConversionDereference Conversion = "dereference"
+	// ConversionReference assigns a pointer to the value.
+	ConversionReference Conversion = "reference"
+	// ConversionValuer assigns the value returned by a driver.Valuer.
+	ConversionValuer Conversion = "valuer"
+	// ConversionConvert converts the value to the destination type.
+	ConversionConvert Conversion = "convert"
+	// ConversionSetter calls a destination setter method with the value.
+	ConversionSetter Conversion = "setter"
+	// ConversionNested copies the value field by field.
+	ConversionNested Conversion = "nested"
+	// ConversionCollection copies elements of a slice or a map field by
+	// field.
+	ConversionCollection Conversion = "collection"
+	// ConversionConverter converts the value with a profile converter.
+	ConversionConverter Conversion = "converter"
+	// ConversionResolver sets the value returned by a profile resolver.
+	ConversionResolver Conversion = "resolver"
+	// ConversionMerge merges elements of a slice into the destination slice
+	// by key.
+	ConversionMerge Conversion = "merge"
+	// ConversionDynamic copies the dynamic value of an interface, or copies
+	// the value to a registered target of an interface.
+	ConversionDynamic Conversion = "dynamic"
+)
+
+// Mapping describes how a destination field is copied.
+type Mapping struct {
+	// Field is the destination field name (or map key).
+	Field string
+	// Source is the source field or method name (or map key).
+	Source string
+	// Method is true if the source is a method.
+	Method bool
+	// Setter is the name of the destination setter method.
+	Setter string
+	// Conversion is the conversion used to copy the value.
+	Conversion Conversion
+	// Skipped is the reason why the field is not copied.
+	Skipped string
+	// Error is the error copies fail with when copying the field.
+	Error string
+	// Default is the default value of the field when left zero.
+	Default string
+	// FromContext is the context key the field is set from.
+	FromContext string
+	// Condition is the condition of the if option.
+	Condition string
+	// Options are the struct tag options of the mapping.
+	Options TagOptions
+	// Nested is the plan of the nested copy.
+	Nested *Plan
+}
+
+// Plan describes how a source type is copied to a destination type.
+type Plan struct {
+	// Src is the source type.
+	Src reflect.Type
+	// Dst is the destination type.
+	Dst reflect.Type
+	// Reversed is true if the plan is the one of From().
+	Reversed bool
+	// Mappings are the destination field mappings.
+	Mappings []Mapping
+	// Unmatched are source fields and methods without destination.
+	Unmatched []string
+	// Errors are the errors of invalid struct tags.
+	Errors []error
+}
+
+// Explain returns the plan describing how the given source type is copied to
+// the given destination type, following the same rules as To() (or From() with
+// reversed options).
+func Explain(src reflect.Type, dst reflect.Type, args ...Options) *Plan {
+	options := Options{}
+	if len(args) > 0 {
+		options = args[0]
+	}
+
+	return explain(indirectType(src), indirectType(dst), options, map[[2]reflect.Type]*Plan{})
+}
+
+// Mapping returns the mapping of the given destination field.
+func (p *Plan) Mapping(field string) (Mapping, bool) {
+	for _, m := range p.Mappings {
+		if m.Field == field {
+			return m, true
+		}
+	}
+	return Mapping{}, false
+}
+
+// String renders the plan as a table.
+func (p *Plan) String() string {
+	var (
+		buf bytes.Buffer
+		w   = tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	)
+
+	fmt.Fprintf(w, "%s -> %s\n", p.Src, p.Dst)
+	fmt.Fprintln(w, "DESTINATION\tSOURCE\tCONVERSION\tNOTE")
+	p.render(w, "", map[*Plan]bool{})
+
+	if len(p.Unmatched) > 0 {
+		fmt.Fprintf(w, "unmatched: %s\n", strings.Join(p.Unmatched, ", "))
+	}
+
+	for _, err := range p.Errors {
+		fmt.Fprintf(w, "error: %s\n", err)
+	}
+
+	w.Flush()
+
+	return buf.String()
+}
+
+// render writes plan mappings with the given path prefix.
+func (p *Plan) render(w *tabwriter.Writer, prefix string, rendered map[*Plan]bool) {
+	rendered[p] = true
+	defer delete(rendered, p)
+
+	for _, m := range p.Mappings {
+		var (
+			source     = m.Source
+			conversion = string(m.Conversion)
+			note       = m.Skipped
+		)
+
+		if source == "" {
+			source = "-"
+		}
+
+		if m.Method {
+			source += "()"
+		}
+
+		if conversion == "" {
+			conversion = "-"
+		}
+
+		if m.Setter != "" {
+			note = m.Setter + "()"
+		}
+
+		if m.Skipped != "" {
+			note = "skipped: " + m.Skipped
+		}
+
+		if m.Error != "" {
+			note = "error: " + m.Error
+		}
+
+		if m.Condition != "" {
+			note = strings.TrimSpace(note + " (if: " + m.Condition + ")")
+		}
+
+		if m.FromContext != "" {
+			note = strings.TrimSpace(note + " (context: " + m.FromContext + ")")
+		}
+
+		if m.Default != "" {
+			note = strings.TrimSpace(note + " (default: " + m.Default + ")")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", joinPath(prefix, m.Field), source, conversion, note)
+
+		if m.Nested != nil && !rendered[m.Nested] {
+			m.Nested.render(w, joinPath(prefix, m.Field), rendered)
+		}
+	}
+}
+
+// explain returns the plan of the given types.
+func explain(src reflect.Type, dst reflect.Type, options Options, plans map[[2]reflect.Type]*Plan) *Plan {
+	key := [2]reflect.Type{src, dst}
+	if plan, ok := plans[key]; ok {
+		return plan
+	}
+
+	plan := &Plan{Src: src, Dst: dst, Reversed: options.Reversed}
+	plans[key] = plan
+
+	plan.Errors = append(getTagErrors(src), getTagErrors(dst)...)
+
+	switch {
+	case src.Kind() == reflect.Struct && dst.Kind() == reflect.Map:
+		explainStructToMap(plan)
+	case src.Kind() == reflect.Map && dst.Kind() == reflect.Struct:
+		explainMapToStruct(plan)
+	case src.Kind() == reflect.Struct && dst.Kind() == reflect.Struct:
+		explainStruct(plan, options, plans)
+	}
+
+	return plan
+}
+
+// explainStruct explains a struct to struct copy from the compiled plan of
+// the copy engine.
+func explainStruct(plan *Plan, options Options, plans map[[2]reflect.Type]*Plan) {
+	var (
+		src      = plan.Src
+		dst      = plan.Dst
+		compiled = getCompiledPlan(src, dst, options.Reversed)
+		mappings = map[string]Mapping{}
+		setters  []Mapping
+	)
+
+	for _, f := range compiled.skipped {
+		mappings[f.dst] = Mapping{Field: f.dst, Source: f.src, Method: f.method, Options: f.options, Skipped: f.reason}
+	}
+
+	for _, f := range compiled.fields {
+		m := Mapping{Field: f.dst, Source: f.src.Name, Options: f.options, Condition: f.options[IfOptionName]}
+
+		switch _, setter := f.options[SetterOptionName]; {
+		case compiled.profile.converter(f.dst) != nil && f.dstFound:
+			m.Conversion = ConversionConverter
+		case setter || !f.dstFound || f.dstField.PkgPath != "":
+			if name, ok := explainSetter(dst, f.dst, f.options); ok {
+				m.Setter, m.Conversion = name, ConversionSetter
+				setters = append(setters, explainCondition(m, f.cond))
+			} else {
+				plan.Unmatched = append(plan.Unmatched, f.src.Name)
+			}
+			continue
+		case f.assign:
+			m.Conversion = ConversionAssign
+		default:
+			m.Conversion, m.Skipped = explainFieldConversion(f.src.Type, f.dstField.Type, f.options)
+		}
+
+		switch m.Conversion {
+		case ConversionNested:
+			m.Nested = explain(indirectType(f.src.Type), indirectType(f.dstField.Type), options, plans)
+		case ConversionCollection, ConversionMerge:
+			srcElem, dstElem := indirectType(f.src.Type.Elem()), indirectType(f.dstField.Type.Elem())
+			if srcElem.Kind() == reflect.Struct && dstElem.Kind() == reflect.Struct {
+				m.Nested = explain(srcElem, dstElem, options, plans)
+			}
+		}
+
+		mappings[f.dst] = explainCondition(m, f.cond)
+	}
+
+	for _, f := range compiled.methods {
+		var (
+			method, _ = reflect.PtrTo(src).MethodByName(f.name)
+			m         = Mapping{Field: f.dst, Source: f.name, Method: true, Options: f.options, Condition: f.options[IfOptionName]}
+		)
+
+		m.Conversion, m.Skipped = explainMethodConversion(method.Type, f.dstField.Type, f.options)
+		if compiled.profile.converter(f.dst) != nil {
+			m.Conversion, m.Skipped = ConversionConverter, ""
+		}
+
+		mappings[f.dst] = explainCondition(m, f.cond)
+	}
+
+	if compiled.profile != nil {
+		for name := range compiled.profile.resolvers {
+			mappings[name] = Mapping{Field: name, Conversion: ConversionResolver}
+		}
+	}
+
+	used := map[string]bool{}
+
+	for _, f := range getTypeFieldNames(dst) {
+		m, ok := mappings[f]
+		if !ok {
+			m = Mapping{Field: f, Skipped: "no matching source field or method"}
+		}
+
+		m.Default = compiled.defaults[f].raw
+
+		if key := compiled.fromContext[f]; key != "" {
+			m.FromContext = key
+			if !ok {
+				m.Skipped = ""
+			}
+		}
+
+		used[m.Source] = ok
+		plan.Mappings = append(plan.Mappings, m)
+	}
+
+	plan.Mappings = append(plan.Mappings, setters...)
+
+	for _, m := range setters {
+		used[m.Source] = true
+	}
+
+	for _, f := range getTypeFieldNames(src) {
+		if !used[f] && !contains(plan.Unmatched, f) {
+			plan.Unmatched = append(plan.Unmatched, f)
+		}
+	}
+}
+
+// explainCondition returns the given mapping with the error of its condition,
+// which fails copies of the field when invalid.
+func explainCondition(m Mapping, cond *condition) Mapping {
+	if cond != nil && cond.err != nil && m.Skipped == "" {
+		m.Error = cond.err.Error()
+	}
+	return m
+}
+
+// explainFieldConversion returns the conversion used to copy a field of the
+// given source type to a field of the given destination type, or the reason
+// why it is skipped.
+func explainFieldConversion(src reflect.Type, dst reflect.Type, tagOptions TagOptions) (Conversion, string) {
+	_, force := tagOptions[ForceOptionName]
+
+	if _, ok := convertExtension(reflect.Zero(src), dst); ok {
+		return ConversionConvert, ""
+	}
+
+	if isTimeConversion(src, dst) && (force || !isNullableType(src)) {
+		return ConversionConvert, ""
+	}
+
+	if isNullableType(src) {
+		if src.AssignableTo(dst) {
+			return ConversionAssign, ""
+		}
+
+		if force {
+			return ConversionValuer, ""
+		}
+
+		return "", "nullable type requires force option"
+	}
+
+	if dst.Kind() == reflect.Interface {
+		if hasTarget(src, dst) {
+			return ConversionDynamic, ""
+		}
+
+		if force {
+			return ConversionAssign, ""
+		}
+
+		return "", "interface requires force option"
+	}
+
+	if src.Kind() == reflect.Interface {
+		return ConversionDynamic, ""
+	}
+
+	if isMerge(src, dst, tagOptions) {
+		return ConversionMerge, ""
+	}
+
+	if src.Kind() == reflect.Ptr && dst.Kind() != reflect.Ptr && src.Elem().AssignableTo(dst) {
+		return ConversionDereference, ""
+	}
+
+	if src.AssignableTo(dst) {
+		return ConversionAssign, ""
+	}
+
+	if isCollection(src, dst) {
+		return ConversionCollection, ""
+	}
+
+	if isNestedType(src) && isNestedType(dst) && !(isStringMap(indirectType(src)) && isStringMap(indirectType(dst))) {
+		return ConversionNested, ""
+	}
+
+	return "", fmt.Sprintf("incompatible types %s and %s", src, dst)
+}
+
+// explainMethodConversion returns the conversion used to copy the result of a
+// method of the given type to a field of the given destination type, or the
+// reason why it is skipped.
+func explainMethodConversion(method reflect.Type, dst reflect.Type, tagOptions TagOptions) (Conversion, string) {
+	var (
+		_, withContext = tagOptions[ContextOptionName]
+		_, force       = tagOptions[ForceOptionName]
+		numIn          = 1
+	)
+
+	// Methods with the context option may not take it
+	if withContext && method.NumIn() == 2 && isContextType(method.In(1)) {
+		numIn = 2
+	}
+
+	if method.NumIn() != numIn || method.NumOut() == 0 {
+		return "", fmt.Sprintf("invalid method signature %s", method)
+	}
+
+	result := method.Out(0)
+
+	if isTimeConversion(result, dst) && (force || !isNullableType(result)) {
+		return ConversionConvert, ""
+	}
+
+	if dst.Kind() == reflect.Ptr && force {
+		if reflect.PtrTo(result).AssignableTo(dst) {
+			return ConversionReference, ""
+		}
+		return "", fmt.Sprintf("incompatible types %s and %s", result, dst)
+	}
+
+	if result.Kind() == reflect.Ptr && force {
+		if result.Elem().AssignableTo(dst) {
+			return ConversionDereference, ""
+		}
+		return "", fmt.Sprintf("incompatible types %s and %s", result, dst)
+	}
+
+	if result.AssignableTo(dst) {
+		return ConversionAssign, ""
+	}
+
+	return "", fmt.Sprintf("incompatible types %s and %s", result, dst)
+}
+
+// explainSetter returns the setter method called for the given destination
+// field.
+func explainSetter(dst reflect.Type, fieldName string, tagOptions TagOptions) (string, bool) {
+	name := tagOptions[SetterOptionName]
+	if name == "" {
+		name = setterName(fieldName)
+	}
+
+	method, ok := reflect.PtrTo(dst).MethodByName(name)
+	if !ok {
+		return "", false
+	}
+
+	t := method.Type
+	if t.NumIn() != 2 || t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		return "", false
+	}
+
+	return name, true
+}
+
+// explainStructToMap explains a struct to map copy.
+func explainStructToMap(plan *Plan) {
+	for _, f := range getTypeFieldNames(plan.Src) {
+		var (
+			srcFieldType, _ = plan.Src.FieldByName(f)
+			tagOptions      = getTagOptions(srcFieldType.Tag.Get(TagName)).direction(plan.Reversed)
+			m               = Mapping{Field: f, Source: f, Options: tagOptions, Conversion: ConversionConvert}
+		)
+
+		if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
+			m.Field = v
+		}
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			m.Conversion, m.Skipped = "", "skip option"
+		}
+
+		plan.Mappings = append(plan.Mappings, m)
+	}
+}
+
+// explainMapToStruct explains a map to struct copy.
+func explainMapToStruct(plan *Plan) {
+	for _, f := range getTypeFieldNames(plan.Dst) {
+		var (
+			dstFieldType, _ = plan.Dst.FieldByName(f)
+			tagOptions      = getTagOptions(dstFieldType.Tag.Get(TagName)).direction(plan.Reversed)
+			m               = Mapping{Field: f, Source: f, Options: tagOptions, Conversion: ConversionConvert}
+		)
+
+		if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
+			m.Source = v
+		}
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			m.Conversion, m.Skipped = "", "skip option"
+		}
+
+		plan.Mappings = append(plan.Mappings, m)
+	}
+}
+
+// contains returns true if the given string is in the given slice.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
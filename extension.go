@@ -0,0 +1,85 @@
+package deepcopier
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Extension customizes copies of all types, for instance to support types of
+// a third-party library. Extensions are registered with Use().
+type Extension interface {
+	// SkipField returns true if the given field of the given struct type is
+	// never copied.
+	SkipField(t reflect.Type, field reflect.StructField) bool
+	// Convert converts the given value to the given type. It returns false if
+	// the extension does not handle these types. Fields of the same bool,
+	// number or string type are only converted if the extension converts
+	// their zero value, which is checked once per mapping.
+	Convert(value reflect.Value, t reflect.Type) (reflect.Value, bool)
+	// AfterCopy is called once the given source struct is copied to the given
+	// destination struct, before context and default values are set.
+	AfterCopy(dst reflect.Value, src reflect.Value, options Options) error
+}
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   []Extension
+)
+
+// Use registers the given extension, used by all copies. Extensions are
+// called in registration order.
+func Use(ext Extension) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+
+	extensions = append(extensions, ext)
+
+	resetCompiledPlans()
+}
+
+// CopyNested copies the given source to the given destination as part of the
+// copy of the given options, for instance to copy nested values from
+// AfterCopy() of an extension: options are kept as is, including the copy
+// direction, depth and cycle tracking.
+func CopyNested(dst interface{}, src interface{}, options Options) error {
+	return process(dst, src, options)
+}
+
+// getExtensions returns registered extensions.
+func getExtensions() []Extension {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+
+	return extensions
+}
+
+// skipExtensionField returns true if an extension skips the given field.
+func skipExtensionField(t reflect.Type, field reflect.StructField) bool {
+	for _, ext := range getExtensions() {
+		if ext.SkipField(t, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertExtension converts the given value to the given type with the first
+// extension handling these types.
+func convertExtension(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	for _, ext := range getExtensions() {
+		if v, ok := ext.Convert(value, t); ok {
+			return v, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// afterCopyExtensions calls AfterCopy of registered extensions.
+func afterCopyExtensions(dst reflect.Value, src reflect.Value, options Options) error {
+	for _, ext := range getExtensions() {
+		if err := ext.AfterCopy(dst, src, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,3 +1,3 @@
 module github.com/ulule/deepcopier
 
-go 1.14
+go 1.23
@@ -0,0 +1,63 @@
+package deepcopier
+
+// BeforeCopier is implemented by destinations that need to be called
+// before being copied from the given source.
+type BeforeCopier interface {
+	BeforeCopy(src interface{}, ctx Context) error
+}
+
+// AfterCopier is implemented by destinations that need to be called
+// after being copied from the given source.
+type AfterCopier interface {
+	AfterCopy(src interface{}, ctx Context) error
+}
+
+// SourceBeforeCopier is implemented by sources that need to be called
+// before being copied to the given destination.
+type SourceBeforeCopier interface {
+	BeforeCopyTo(dst interface{}, ctx Context) error
+}
+
+// SourceAfterCopier is implemented by sources that need to be called
+// after being copied to the given destination.
+type SourceAfterCopier interface {
+	AfterCopyTo(dst interface{}, ctx Context) error
+}
+
+// beforeCopy calls source and destination hooks before copy.
+func beforeCopy(dst interface{}, src interface{}, options Options) error {
+	ctx := newContext(dst, src, options.path, options)
+
+	if h, ok := getMethodReceiver(src).Interface().(SourceBeforeCopier); ok {
+		if err := h.BeforeCopyTo(dst, ctx); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := dst.(BeforeCopier); ok {
+		if err := h.BeforeCopy(src, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// afterCopy calls destination and source hooks after copy.
+func afterCopy(dst interface{}, src interface{}, options Options) error {
+	ctx := newContext(dst, src, options.path, options)
+
+	if h, ok := dst.(AfterCopier); ok {
+		if err := h.AfterCopy(src, ctx); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := getMethodReceiver(src).Interface().(SourceAfterCopier); ok {
+		if err := h.AfterCopyTo(dst, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package deepcopier
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// nestedMapType is the type of maps nested structs are copied to.
+var nestedMapType = reflect.TypeOf(map[string]interface{}{})
+
+// copyStructToMap copies the given struct fields into the given map.
+// Keys are field names, or the field option of the struct tag when defined.
+func copyStructToMap(dst reflect.Value, src reflect.Value, options Options) error {
+	if dst.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("destination map %s must have string keys", dst.Type())
+	}
+
+	if dst.IsNil() {
+		if !dst.CanSet() {
+			return fmt.Errorf("destination %s is unaddressable", dst.Type())
+		}
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+
+	var (
+		keyType  = dst.Type().Key()
+		elemType = dst.Type().Elem()
+		observer = options.observer()
+	)
+
+	for _, f := range getFieldNames(src.Interface()) {
+		var (
+			srcFieldType, _ = src.Type().FieldByName(f)
+			srcFieldValue   = src.FieldByName(f)
+			tagOptions      = getTagOptions(srcFieldType.Tag.Get(TagName)).direction(options.Reversed)
+			key             = srcFieldType.Name
+		)
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			continue
+		}
+
+		if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
+			key = v
+		}
+
+		value, ok, err := toMapValue(srcFieldValue, elemType, options.withPath(key))
+		if err != nil {
+			return newFieldError(key, err)
+		}
+
+		if observer != nil {
+			observer.OnField(joinPath(options.path, key), ok)
+		}
+
+		if !ok {
+			continue
+		}
+
+		k := reflect.ValueOf(key).Convert(keyType)
+		if old := dst.MapIndex(k); old.IsValid() {
+			options.record(key, old.Interface(), value.Interface())
+		} else {
+			options.record(key, nil, value.Interface())
+		}
+
+		dst.SetMapIndex(k, value)
+	}
+
+	return nil
+}
+
+// copyMapToStruct copies the given map values into the given struct fields.
+// Values are looked up by field name, or by the field option of the struct tag
+// when defined.
+func copyMapToStruct(dst reflect.Value, src reflect.Value, options Options) error {
+	if src.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("source map %s must have string keys", src.Type())
+	}
+
+	if src.IsNil() {
+		return nil
+	}
+
+	var (
+		keyType  = src.Type().Key()
+		observer = options.observer()
+	)
+
+	for _, f := range getFieldNames(dst.Addr().Interface()) {
+		var (
+			dstFieldType, _ = dst.Type().FieldByName(f)
+			dstFieldValue   = dst.FieldByName(f)
+			tagOptions      = getTagOptions(dstFieldType.Tag.Get(TagName)).direction(options.Reversed)
+			key             = dstFieldType.Name
+		)
+
+		if _, ok := tagOptions[SkipOptionName]; ok {
+			continue
+		}
+
+		if v, ok := tagOptions[FieldOptionName]; ok && v != "" {
+			key = v
+		}
+
+		value := src.MapIndex(reflect.ValueOf(key).Convert(keyType))
+		if !value.IsValid() {
+			continue
+		}
+
+		copied, err := setFromMapValue(dstFieldValue, dstFieldType.Name, value, options)
+		if err != nil {
+			return newFieldError(dstFieldType.Name, err)
+		}
+
+		if observer != nil {
+			observer.OnField(joinPath(options.path, dstFieldType.Name), copied)
+		}
+	}
+
+	return nil
+}
+
+// toMapValue returns the given field value as a map value of the given type.
+// Nested structs are converted to maps when the map holds interfaces, within
+// the maximum depth and with the cycle policy of nested structs.
+func toMapValue(value reflect.Value, t reflect.Type, options Options) (reflect.Value, bool, error) {
+	if t.Kind() == reflect.Interface && isNestedStruct(value) {
+		if options.maxDepthReached() {
+			return reflect.Value{}, false, nil
+		}
+
+		ptr := reflect.New(nestedMapType)
+
+		v, found, err := checkCycle(value, ptr.Type(), options)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+
+		if found {
+			if !v.IsValid() {
+				return reflect.Value{}, false, nil
+			}
+			return v.Elem(), true, nil
+		}
+
+		ptr.Elem().Set(reflect.MakeMap(nestedMapType))
+
+		if err := process(ptr.Interface(), value.Interface(), options.withoutChanges()); err != nil {
+			return reflect.Value{}, false, err
+		}
+
+		return ptr.Elem(), true, nil
+	}
+
+	// Keep nil values as untyped nil
+	if t.Kind() == reflect.Interface && isNil(value) {
+		return reflect.Zero(t), true, nil
+	}
+
+	v, ok := convert(value, t)
+
+	return v, ok, nil
+}
+
+// setFromMapValue sets the given map value to the given field, converting it
+// to the field type or copying it into the field when it is a nested map.
+// Values which cannot be converted leave the field unchanged, in which case it
+// returns false.
+func setFromMapValue(field reflect.Value, name string, value reflect.Value, options Options) (bool, error) {
+	v, ok, err := fromMapValue(value, field.Type(), options.withPath(name))
+	if err != nil || !ok {
+		return false, err
+	}
+
+	setField(field, name, options, v)
+
+	return true, nil
+}
+
+// fromMapValue returns the given map value converted to the given type.
+// Nested maps are copied into structs and slices (e.g. []interface{} of
+// decoded JSON) are converted element by element. It returns false if the
+// value or one of its elements cannot be converted.
+func fromMapValue(value reflect.Value, t reflect.Type, options Options) (reflect.Value, bool, error) {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false, nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Type().AssignableTo(t) {
+		return value, true, nil
+	}
+
+	switch {
+	case value.Kind() == reflect.Map && indirectType(t).Kind() == reflect.Struct:
+		if options.maxDepthReached() {
+			return reflect.Value{}, false, nil
+		}
+
+		v := reflect.New(indirectType(t))
+		if err := process(v.Interface(), value.Interface(), options.withoutChanges()); err != nil {
+			return reflect.Value{}, false, err
+		}
+
+		if t.Kind() == reflect.Ptr {
+			return v, true, nil
+		}
+
+		return v.Elem(), true, nil
+	case value.Kind() == reflect.Slice && t.Kind() == reflect.Slice:
+		if value.IsNil() {
+			return reflect.Zero(t), true, nil
+		}
+
+		v := reflect.MakeSlice(t, value.Len(), value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			// Null elements are zero elements
+			if isNil(value.Index(i)) {
+				continue
+			}
+
+			elem, ok, err := fromMapValue(value.Index(i), t.Elem(), options.withPath(strconv.Itoa(i)))
+			if err != nil {
+				return reflect.Value{}, false, newFieldError(strconv.Itoa(i), err)
+			}
+
+			if !ok {
+				return reflect.Value{}, false, nil
+			}
+
+			v.Index(i).Set(elem)
+		}
+
+		return v, true, nil
+	}
+
+	v, ok := convert(value, t)
+
+	return v, ok, nil
+}
+
+// isNestedStruct returns true if the given value is a struct (or a pointer
+// to a struct) that should be copied field by field.
+func isNestedStruct(value reflect.Value) bool {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return false
+	}
+
+	return indirectType(value.Type()).Kind() == reflect.Struct && isNestedType(value.Type())
+}
+
+// isNestedType returns true if values of the given type can be copied field
+// by field.
+func isNestedType(t reflect.Type) bool {
+	t = indirectType(t)
+
+	if t.Kind() == reflect.Struct {
+		return !isNullableType(t) && len(getTypeFieldNames(t)) > 0
+	}
+
+	return isStringMap(t)
+}
+
+// isNil returns true if the given value is a nil pointer or interface.
+func isNil(value reflect.Value) bool {
+	return (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil()
+}
+
+// isStringMap returns true if the given type is a map with string keys.
+func isStringMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
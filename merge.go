@@ -0,0 +1,139 @@
+package deepcopier
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// OrphanKeep keeps destination elements missing from the source.
+	OrphanKeep = "keep"
+	// OrphanDelete removes destination elements missing from the source.
+	OrphanDelete = "delete"
+)
+
+// isMerge returns true if the given types are slices of structs merged by key
+// with the given tag options.
+func isMerge(src reflect.Type, dst reflect.Type, tagOptions TagOptions) bool {
+	if tagOptions[MergeKeyOptionName] == "" {
+		return false
+	}
+
+	if src.Kind() != reflect.Slice || dst.Kind() != reflect.Slice {
+		return false
+	}
+
+	return indirectType(src.Elem()).Kind() == reflect.Struct && indirectType(dst.Elem()).Kind() == reflect.Struct
+}
+
+// mergeCollection merges elements of the given source slice into the given
+// destination slice. Elements are matched by the destination field of the
+// mergekey option: matching elements are copied in place and other ones are
+// appended. Destination elements missing from the source are removed with the
+// "orphan:delete" option. Changes and errors of elements copied in place have
+// the index of the destination element, errors of appended elements the index
+// of the source element.
+func mergeCollection(dst reflect.Value, name string, src reflect.Value, tagOptions TagOptions, options Options) error {
+	var (
+		key      = tagOptions[MergeKeyOptionName]
+		srcElem  = indirectType(src.Type().Elem())
+		dstElem  = indirectType(dst.Type().Elem())
+		dstKey   reflect.StructField
+		srcKey   []int
+		matched  = make([]bool, dst.Len())
+		indexes  = map[interface{}]int{}
+		appended []reflect.Value
+	)
+
+	dstKey, ok := dstElem.FieldByName(key)
+	if !ok || !dstKey.Type.Comparable() {
+		return fmt.Errorf("merge key %s is not a comparable field of %s", key, dstElem)
+	}
+
+	for _, f := range getCompiledPlan(srcElem, dstElem, options.Reversed).fields {
+		if f.dst == key {
+			srcKey = f.src.Index
+		}
+	}
+
+	if srcKey == nil {
+		return fmt.Errorf("merge key %s is not copied from %s", key, srcElem)
+	}
+
+	for i := 0; i < dst.Len(); i++ {
+		elem := reflect.Indirect(dst.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+
+		k := elem.FieldByIndex(dstKey.Index)
+		if _, ok := indexes[k.Interface()]; !ok && !k.IsZero() {
+			indexes[k.Interface()] = i
+		}
+	}
+
+	// New elements are copied like elements of collections
+	elemOptions := options.withoutChanges()
+	elemOptions.Parallel = 0
+
+	for i := 0; i < src.Len(); i++ {
+		elem := src.Index(i)
+		if isNil(elem) {
+			continue
+		}
+
+		if j, ok := lookupMergeKey(indexes, reflect.Indirect(elem).FieldByIndex(srcKey), dstKey.Type); ok {
+			matched[j] = true
+
+			// Existing elements are copied in place
+			existing := dst.Index(j)
+			if existing.Kind() != reflect.Ptr {
+				existing = existing.Addr()
+			}
+
+			if err := process(existing.Interface(), elem.Interface(), options.withPath(name).withPath(strconv.Itoa(j))); err != nil {
+				return newFieldError(strconv.Itoa(j), err)
+			}
+			continue
+		}
+
+		v := reflect.New(dst.Type().Elem()).Elem()
+		if err := copyElement(v, elem, elemOptions.withPath(strconv.Itoa(i))); err != nil {
+			return newFieldError(strconv.Itoa(i), err)
+		}
+
+		appended = append(appended, v)
+	}
+
+	result := reflect.MakeSlice(dst.Type(), 0, dst.Len()+len(appended))
+	for i := 0; i < dst.Len(); i++ {
+		if matched[i] || tagOptions[OrphanOptionName] != OrphanDelete {
+			result = reflect.Append(result, dst.Index(i))
+		}
+	}
+
+	result = reflect.Append(result, appended...)
+
+	// Slices updated in place only are kept
+	if len(appended) == 0 && result.Len() == dst.Len() {
+		return nil
+	}
+
+	setField(dst, name, options, result)
+
+	return nil
+}
+
+// lookupMergeKey returns the index of the destination element of the given
+// source key, converted to the destination key type. Zero keys never match.
+func lookupMergeKey(indexes map[interface{}]int, key reflect.Value, t reflect.Type) (int, bool) {
+	k, ok := convert(key, t)
+	if !ok || k.IsZero() {
+		return 0, false
+	}
+
+	i, ok := indexes[k.Interface()]
+
+	return i, ok
+}
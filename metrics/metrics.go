@@ -0,0 +1,125 @@
+// Package metrics is a deepcopier observer recording counts and latencies of
+// copies in expvar variables, published by the expvar HTTP handler.
+//
+//	deepcopier.SetObserver(metrics.New("deepcopier"))
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// buckets are upper bounds of latency buckets.
+var buckets = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Observer records copies in the following variables of an expvar map:
+//
+//   - copies: number of copies
+//   - errors: number of failed copies
+//   - in_flight: number of copies in progress
+//   - fields_copied: number of copied fields
+//   - fields_skipped: number of skipped fields
+//   - duration_ns: total duration of copies in nanoseconds
+//   - latency: number of copies by latency bucket (le_1µs... le_1s, inf)
+//   - copies_by_type: number of copies by "source -> destination" types
+//   - duration_ns_by_type: total duration of copies by types in nanoseconds
+type Observer struct {
+	vars          *expvar.Map
+	copies        *expvar.Int
+	errors        *expvar.Int
+	inFlight      *expvar.Int
+	fieldsCopied  *expvar.Int
+	fieldsSkipped *expvar.Int
+	duration      *expvar.Int
+	latency       []*expvar.Int
+	byType        *expvar.Map
+	durationType  *expvar.Map
+}
+
+// New returns an observer publishing its variables in an expvar map of the
+// given name. Like expvar.Publish, it panics if the name is already used.
+func New(name string) *Observer {
+	o := &Observer{
+		vars:          expvar.NewMap(name),
+		copies:        new(expvar.Int),
+		errors:        new(expvar.Int),
+		inFlight:      new(expvar.Int),
+		fieldsCopied:  new(expvar.Int),
+		fieldsSkipped: new(expvar.Int),
+		duration:      new(expvar.Int),
+		byType:        new(expvar.Map).Init(),
+		durationType:  new(expvar.Map).Init(),
+	}
+
+	latency := new(expvar.Map).Init()
+	for _, b := range buckets {
+		v := new(expvar.Int)
+		latency.Set("le_"+b.String(), v)
+		o.latency = append(o.latency, v)
+	}
+
+	inf := new(expvar.Int)
+	latency.Set("inf", inf)
+	o.latency = append(o.latency, inf)
+
+	o.vars.Set("copies", o.copies)
+	o.vars.Set("errors", o.errors)
+	o.vars.Set("in_flight", o.inFlight)
+	o.vars.Set("fields_copied", o.fieldsCopied)
+	o.vars.Set("fields_skipped", o.fieldsSkipped)
+	o.vars.Set("duration_ns", o.duration)
+	o.vars.Set("latency", latency)
+	o.vars.Set("copies_by_type", o.byType)
+	o.vars.Set("duration_ns_by_type", o.durationType)
+
+	return o
+}
+
+// Vars returns the expvar map of the observer.
+func (o *Observer) Vars() *expvar.Map {
+	return o.vars
+}
+
+// OnCopyStart implements deepcopier.Observer.
+func (o *Observer) OnCopyStart(dst interface{}, src interface{}) {
+	o.inFlight.Add(1)
+}
+
+// OnField implements deepcopier.Observer.
+func (o *Observer) OnField(path string, copied bool) {
+	if copied {
+		o.fieldsCopied.Add(1)
+	} else {
+		o.fieldsSkipped.Add(1)
+	}
+}
+
+// OnCopyEnd implements deepcopier.Observer.
+func (o *Observer) OnCopyEnd(dst interface{}, src interface{}, duration time.Duration, err error) {
+	o.inFlight.Add(-1)
+	o.copies.Add(1)
+	o.duration.Add(int64(duration))
+
+	if err != nil {
+		o.errors.Add(1)
+	}
+
+	i := 0
+	for i < len(buckets) && duration > buckets[i] {
+		i++
+	}
+	o.latency[i].Add(1)
+
+	key := fmt.Sprintf("%T -> %T", src, dst)
+	o.byType.Add(key, 1)
+	o.durationType.Add(key, int64(duration))
+}
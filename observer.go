@@ -0,0 +1,66 @@
+package deepcopier
+
+import (
+	"sync"
+	"time"
+)
+
+// Observer is notified of copies, for instance to trace them or to record
+// metrics. Observers must be safe for concurrent use.
+type Observer interface {
+	// OnCopyStart is called before the given source is copied to the given
+	// destination.
+	OnCopyStart(dst interface{}, src interface{})
+	// OnField is called once a destination field of the given path, nested
+	// fields included, is copied or skipped.
+	OnField(path string, copied bool)
+	// OnCopyEnd is called once the given source is copied to the given
+	// destination, with the duration and the error of the copy.
+	OnCopyEnd(dst interface{}, src interface{}, duration time.Duration, err error)
+}
+
+var (
+	observerMu     sync.RWMutex
+	globalObserver Observer
+)
+
+// SetObserver sets the observer of all copies without observer given to the
+// WithObserver() method. A nil observer removes it.
+func SetObserver(o Observer) {
+	observerMu.Lock()
+	defer observerMu.Unlock()
+
+	globalObserver = o
+}
+
+// observer returns the observer of the copy.
+func (o Options) observer() Observer {
+	if o.Observer != nil {
+		return o.Observer
+	}
+
+	observerMu.RLock()
+	defer observerMu.RUnlock()
+
+	return globalObserver
+}
+
+// observe calls the given copy function between OnCopyStart and OnCopyEnd of
+// the observer.
+func observe(dst interface{}, src interface{}, options Options, fn func() error) error {
+	observer := options.observer()
+	if observer == nil {
+		return fn()
+	}
+
+	observer.OnCopyStart(dst, src)
+
+	var (
+		start = time.Now()
+		err   = fn()
+	)
+
+	observer.OnCopyEnd(dst, src, time.Since(start), err)
+
+	return err
+}
@@ -0,0 +1,230 @@
+package deepcopier
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Profile declares in code how a source type is copied to a destination type,
+// for types which cannot be tagged. Fields are destination field names.
+// Profiles are merged with struct tags and take precedence over them.
+type Profile[Src, Dst any] struct {
+	// Fields maps destination fields to source field or method names.
+	Fields map[string]string
+	// Skip lists destination fields which are not copied.
+	Skip []string
+	// Force lists destination fields copied with the force option.
+	Force []string
+	// Converters convert source values before they are set to destination
+	// fields.
+	Converters map[string]func(value interface{}) (interface{}, error)
+	// Resolvers return values of destination fields from the whole source.
+	// They are called after other fields are copied.
+	Resolvers map[string]func(src Src) (interface{}, error)
+}
+
+// profile is a registered Profile.
+type profile struct {
+	fields     map[string]string
+	sources    map[string]string
+	skip       map[string]bool
+	force      map[string]bool
+	converters map[string]func(value interface{}) (interface{}, error)
+	resolvers  map[string]func(src reflect.Value) (interface{}, error)
+}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[[2]reflect.Type]*profile{}
+)
+
+// Register registers the profile of the given struct types, used whenever a
+// Src is copied to a Dst, by To() or From(), including nested copies. A
+// previous profile of the same types is replaced.
+//
+// Register panics if Src or Dst are not struct types or if the profile
+// references unknown fields.
+func Register[Src, Dst any](p Profile[Src, Dst]) {
+	var (
+		src = reflect.TypeOf((*Src)(nil)).Elem()
+		dst = reflect.TypeOf((*Dst)(nil)).Elem()
+	)
+
+	if src.Kind() != reflect.Struct || dst.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("deepcopier: cannot register profile of %s to %s: types must be structs", src, dst))
+	}
+
+	var (
+		sources = getTypeFieldNames(src)
+		targets = getTypeFieldNames(dst)
+	)
+
+	sources = append(sources, getTypeMethodNames(src)...)
+
+	check := func(names []string, known []string) {
+		for _, name := range names {
+			if !contains(known, name) {
+				panic(fmt.Sprintf("deepcopier: unknown field %s in profile of %s to %s", name, src, dst))
+			}
+		}
+	}
+
+	pr := &profile{
+		fields:     map[string]string{},
+		sources:    map[string]string{},
+		skip:       map[string]bool{},
+		force:      map[string]bool{},
+		converters: map[string]func(value interface{}) (interface{}, error){},
+		resolvers:  map[string]func(src reflect.Value) (interface{}, error){},
+	}
+
+	for name, source := range p.Fields {
+		check([]string{name}, targets)
+		check([]string{source}, sources)
+
+		pr.fields[name] = source
+		pr.sources[source] = name
+	}
+
+	check(p.Skip, targets)
+	for _, name := range p.Skip {
+		pr.skip[name] = true
+	}
+
+	check(p.Force, targets)
+	for _, name := range p.Force {
+		pr.force[name] = true
+	}
+
+	for name, fn := range p.Converters {
+		check([]string{name}, targets)
+		pr.converters[name] = fn
+	}
+
+	for name, fn := range p.Resolvers {
+		check([]string{name}, targets)
+
+		fn := fn
+		pr.resolvers[name] = func(v reflect.Value) (interface{}, error) {
+			return fn(v.Interface().(Src))
+		}
+	}
+
+	profilesMu.Lock()
+	profiles[[2]reflect.Type{src, dst}] = pr
+	profilesMu.Unlock()
+
+	resetCompiledPlans()
+}
+
+// getProfile returns the profile registered for the given types, or nil.
+func getProfile(src reflect.Type, dst reflect.Type) *profile {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	return profiles[[2]reflect.Type{indirectType(src), indirectType(dst)}]
+}
+
+// resolve returns the destination field name and tag options of the given
+// source field or method once the profile is applied, or an empty name if the
+// profile maps the destination field to another source.
+func (p *profile) resolve(dst reflect.Type, source string, name string, tagOptions TagOptions, reversed bool) (string, TagOptions) {
+	if p == nil {
+		return name, tagOptions
+	}
+
+	if target, ok := p.sources[source]; ok {
+		// Options of the new destination field are declared on it
+		if target != name && !reversed {
+			tagOptions = nil
+			if f, ok := indirectType(dst).FieldByName(target); ok {
+				tagOptions = getTagOptions(f.Tag.Get(TagName)).direction(false)
+			}
+		}
+		name = target
+	} else if s, ok := p.fields[name]; ok && s != source {
+		return "", nil
+	}
+
+	if name == "" {
+		return name, tagOptions
+	}
+
+	options := make(TagOptions, len(tagOptions)+1)
+	for k, v := range tagOptions {
+		options[k] = v
+	}
+
+	if _, ok := p.fields[name]; ok {
+		delete(options, SkipOptionName)
+	}
+
+	if p.skip[name] {
+		options[SkipOptionName] = ""
+	}
+
+	if p.force[name] {
+		options[ForceOptionName] = ""
+	}
+
+	return name, options
+}
+
+// converter returns the converter of the given destination field, or nil.
+func (p *profile) converter(name string) func(value interface{}) (interface{}, error) {
+	if p == nil {
+		return nil
+	}
+	return p.converters[name]
+}
+
+// applyResolvers sets destination fields returned by resolvers of the
+// profile.
+func (p *profile) applyResolvers(dst reflect.Value, src reflect.Value, options Options) error {
+	if p == nil {
+		return nil
+	}
+
+	for name, fn := range p.resolvers {
+		value, err := fn(src)
+		if err != nil {
+			return newFieldError(name, err)
+		}
+
+		if err := setValue(dst.FieldByName(name), name, value, options); err != nil {
+			return newFieldError(name, err)
+		}
+	}
+
+	return nil
+}
+
+// convertValue sets the given source value, converted by the given profile
+// converter, to the given field.
+func convertValue(field reflect.Value, name string, fn func(value interface{}) (interface{}, error), value reflect.Value, options Options) error {
+	v, err := fn(value.Interface())
+	if err != nil {
+		return err
+	}
+
+	return setValue(field, name, v, options)
+}
+
+// setValue sets the given value returned by a converter or a resolver to the
+// given field, converted to the field type.
+func setValue(field reflect.Value, name string, value interface{}, options Options) error {
+	if value == nil {
+		setField(field, name, options, reflect.Zero(field.Type()))
+		return nil
+	}
+
+	v, ok := convert(reflect.ValueOf(value), field.Type())
+	if !ok {
+		return fmt.Errorf("cannot use %T as %s", value, field.Type())
+	}
+
+	setField(field, name, options, v)
+
+	return nil
+}
@@ -0,0 +1,17 @@
+module github.com/ulule/deepcopier/protobuf
+
+go 1.23
+
+require (
+	github.com/stretchr/testify v1.5.1
+	github.com/ulule/deepcopier v0.0.0
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+replace github.com/ulule/deepcopier => ../
@@ -0,0 +1,546 @@
+// Package protobuf is a deepcopier extension copying generated protobuf
+// messages: internal fields are skipped, well-known types are converted to and
+// from Go types, and oneof cases are mapped to plain struct fields.
+//
+//	deepcopier.Use(protobuf.Extension())
+package protobuf
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ulule/deepcopier"
+)
+
+var (
+	legacyMessageType = reflect.TypeOf((*protoadapt.MessageV1)(nil)).Elem()
+	timestampType     = reflect.TypeOf((*timestamppb.Timestamp)(nil))
+	durationType      = reflect.TypeOf((*durationpb.Duration)(nil))
+	timeType          = reflect.TypeOf(time.Time{})
+	goDurationType    = reflect.TypeOf(time.Duration(0))
+
+	// wrapperTypes are wrapper types, converted to and from the type of their
+	// Value field.
+	wrapperTypes = map[reflect.Type]bool{
+		reflect.TypeOf((*wrapperspb.StringValue)(nil)): true,
+		reflect.TypeOf((*wrapperspb.BytesValue)(nil)):  true,
+		reflect.TypeOf((*wrapperspb.BoolValue)(nil)):   true,
+		reflect.TypeOf((*wrapperspb.Int32Value)(nil)):  true,
+		reflect.TypeOf((*wrapperspb.Int64Value)(nil)):  true,
+		reflect.TypeOf((*wrapperspb.UInt32Value)(nil)): true,
+		reflect.TypeOf((*wrapperspb.UInt64Value)(nil)): true,
+		reflect.TypeOf((*wrapperspb.FloatValue)(nil)):  true,
+		reflect.TypeOf((*wrapperspb.DoubleValue)(nil)): true,
+	}
+)
+
+// Extension returns the deepcopier extension of protobuf messages.
+func Extension() deepcopier.Extension {
+	return extension{}
+}
+
+// extension implements deepcopier.Extension.
+type extension struct{}
+
+// SkipField skips internal fields of messages (XXX_ fields of older
+// generators) and oneof fields, copied by AfterCopy.
+func (extension) SkipField(t reflect.Type, field reflect.StructField) bool {
+	if !reflect.PtrTo(t).Implements(legacyMessageType) {
+		return false
+	}
+
+	return strings.HasPrefix(field.Name, "XXX_") || field.Tag.Get("protobuf_oneof") != ""
+}
+
+// Convert converts well-known types to and from Go types:
+// timestamps to time.Time, durations to time.Duration and wrappers to
+// pointers or values of their wrapped type.
+func (extension) Convert(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	switch src := value.Type(); {
+	case src == timestampType:
+		return fromTimestamp(value, t)
+	case t == timestampType:
+		return toTimestamp(value)
+	case src == durationType:
+		return fromDuration(value, t)
+	case t == durationType:
+		return toDuration(value)
+	case wrapperTypes[src]:
+		return fromWrapper(value, t)
+	case wrapperTypes[t]:
+		return toWrapper(value, t)
+	}
+
+	return reflect.Value{}, false
+}
+
+// AfterCopy copies oneof fields between messages, or between oneof cases of a
+// message and the fields of the same name of a plain struct.
+func (extension) AfterCopy(dst reflect.Value, src reflect.Value, options deepcopier.Options) error {
+	var (
+		srcMsg, srcOK = message(src)
+		dstMsg, dstOK = message(dst)
+	)
+
+	switch {
+	case srcOK && dstOK:
+		copyOneofs(dstMsg, srcMsg)
+	case srcOK:
+		return fromOneofs(dst, srcMsg, options)
+	case dstOK:
+		return toOneofs(dstMsg, src, options)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Well-known types
+// ----------------------------------------------------------------------------
+
+// fromTimestamp converts a timestamp to a time.Time or a *time.Time.
+func fromTimestamp(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	ts := value.Interface().(*timestamppb.Timestamp)
+
+	switch {
+	case t == timeType && ts == nil:
+		return reflect.Zero(t), true
+	case t == timeType:
+		return reflect.ValueOf(ts.AsTime()), true
+	case t == reflect.PtrTo(timeType) && ts == nil:
+		return reflect.Zero(t), true
+	case t == reflect.PtrTo(timeType):
+		tm := ts.AsTime()
+		return reflect.ValueOf(&tm), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// toTimestamp converts a time.Time or a *time.Time to a timestamp. Zero times
+// are converted to nil timestamps.
+func toTimestamp(value reflect.Value) (reflect.Value, bool) {
+	if value.Type() == reflect.PtrTo(timeType) {
+		if value.IsNil() {
+			return reflect.Zero(timestampType), true
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() != timeType {
+		return reflect.Value{}, false
+	}
+
+	tm := value.Interface().(time.Time)
+	if tm.IsZero() {
+		return reflect.Zero(timestampType), true
+	}
+
+	return reflect.ValueOf(timestamppb.New(tm)), true
+}
+
+// fromDuration converts a duration to a time.Duration or a *time.Duration.
+func fromDuration(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	d := value.Interface().(*durationpb.Duration)
+
+	switch {
+	case t == goDurationType:
+		return reflect.ValueOf(d.AsDuration()), true
+	case t == reflect.PtrTo(goDurationType) && d == nil:
+		return reflect.Zero(t), true
+	case t == reflect.PtrTo(goDurationType):
+		v := d.AsDuration()
+		return reflect.ValueOf(&v), true
+	}
+
+	return reflect.Value{}, false
+}
+
+// toDuration converts a time.Duration or a *time.Duration to a duration.
+func toDuration(value reflect.Value) (reflect.Value, bool) {
+	if value.Type() == reflect.PtrTo(goDurationType) {
+		if value.IsNil() {
+			return reflect.Zero(durationType), true
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() != goDurationType {
+		return reflect.Value{}, false
+	}
+
+	return reflect.ValueOf(durationpb.New(value.Interface().(time.Duration))), true
+}
+
+// fromWrapper converts a wrapper to a pointer or a value of the wrapped type.
+// Nil wrappers are converted to nil pointers or zero values.
+func fromWrapper(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	var (
+		target = t
+		native = value.Type().Elem()
+	)
+
+	if t.Kind() == reflect.Ptr {
+		target = t.Elem()
+	}
+
+	field, _ := native.FieldByName("Value")
+	if !convertible(field.Type, target) {
+		return reflect.Value{}, false
+	}
+
+	if value.IsNil() {
+		return reflect.Zero(t), true
+	}
+
+	v := value.Elem().FieldByName("Value").Convert(target)
+	if t.Kind() != reflect.Ptr {
+		return v, true
+	}
+
+	ptr := reflect.New(target)
+	ptr.Elem().Set(v)
+
+	return ptr, true
+}
+
+// toWrapper converts a pointer or a value to a wrapper. Nil pointers are
+// converted to nil wrappers.
+func toWrapper(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	var (
+		source   = value.Type()
+		field, _ = t.Elem().FieldByName("Value")
+	)
+
+	if source.Kind() == reflect.Ptr {
+		source = source.Elem()
+	}
+
+	if !convertible(source, field.Type) {
+		return reflect.Value{}, false
+	}
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Zero(t), true
+		}
+		value = value.Elem()
+	}
+
+	w := reflect.New(t.Elem())
+	w.Elem().FieldByName("Value").Set(value.Convert(field.Type))
+
+	return w, true
+}
+
+// convertible returns true if values of the given type can be converted to the
+// other type without changing their meaning: types of the same kind, or
+// numbers.
+func convertible(from reflect.Type, to reflect.Type) bool {
+	if from.Kind() == to.Kind() {
+		return from.ConvertibleTo(to)
+	}
+	return isNumber(from) && isNumber(to)
+}
+
+// isNumber returns true if the given type is an integer or a float.
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// ----------------------------------------------------------------------------
+// Oneofs
+// ----------------------------------------------------------------------------
+
+// message returns the protobuf message of the given struct value.
+func message(v reflect.Value) (protoreflect.Message, bool) {
+	if v.Kind() != reflect.Struct || !reflect.PtrTo(v.Type()).Implements(legacyMessageType) {
+		return nil, false
+	}
+
+	ptr := reflect.New(v.Type())
+	if v.CanAddr() {
+		ptr = v.Addr()
+	} else {
+		ptr.Elem().Set(v)
+	}
+
+	if m, ok := ptr.Interface().(proto.Message); ok {
+		return m.ProtoReflect(), true
+	}
+
+	return protoadapt.MessageV2Of(ptr.Interface().(protoadapt.MessageV1)).ProtoReflect(), true
+}
+
+// copyOneofs copies oneof fields of the source message to oneof fields of the
+// same name of the destination message.
+func copyOneofs(dst protoreflect.Message, src protoreflect.Message) {
+	oneofs := dst.Descriptor().Oneofs()
+
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+
+		srcOd := src.Descriptor().Oneofs().ByName(od.Name())
+		if srcOd == nil {
+			continue
+		}
+
+		if fd := dst.WhichOneof(od); fd != nil {
+			dst.Clear(fd)
+		}
+
+		srcFd := src.WhichOneof(srcOd)
+		if srcFd == nil {
+			continue
+		}
+
+		fd := od.Fields().ByName(srcFd.Name())
+		if fd == nil || fd.Kind() != srcFd.Kind() {
+			continue
+		}
+
+		v := src.Get(srcFd)
+
+		switch {
+		case fd.Message() != nil:
+			if fd.Message().FullName() != srcFd.Message().FullName() {
+				continue
+			}
+			v = protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+		case fd.Kind() == protoreflect.BytesKind:
+			v = protoreflect.ValueOfBytes(append([]byte(nil), v.Bytes()...))
+		}
+
+		dst.Set(fd, v)
+	}
+}
+
+// fromOneofs copies set oneof cases of the source message to the fields of
+// the same name of the destination struct.
+func fromOneofs(dst reflect.Value, src protoreflect.Message, options deepcopier.Options) error {
+	oneofs := src.Descriptor().Oneofs()
+
+	for i := 0; i < oneofs.Len(); i++ {
+		fd := src.WhichOneof(oneofs.Get(i))
+		if fd == nil {
+			continue
+		}
+
+		field := dst.FieldByName(goCamelCase(string(fd.Name())))
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+
+		if err := fromValue(field, fd, src.Get(fd), options); err != nil {
+			return &deepcopier.FieldError{Path: goCamelCase(string(fd.Name())), Err: err}
+		}
+	}
+
+	return nil
+}
+
+// fromValue sets the given field to the given protobuf value.
+func fromValue(field reflect.Value, fd protoreflect.FieldDescriptor, v protoreflect.Value, options deepcopier.Options) error {
+	var value reflect.Value
+
+	switch {
+	case fd.Message() != nil:
+		value = reflect.ValueOf(proto.Clone(v.Message().Interface()))
+	case fd.Enum() != nil:
+		value = reflect.ValueOf(int32(v.Enum()))
+	case fd.Kind() == protoreflect.BytesKind:
+		value = reflect.ValueOf(append([]byte(nil), v.Bytes()...))
+	default:
+		value = reflect.ValueOf(v.Interface())
+	}
+
+	if value.Type().AssignableTo(field.Type()) {
+		field.Set(value)
+		return nil
+	}
+
+	if converted, ok := (extension{}).Convert(value, field.Type()); ok {
+		field.Set(converted)
+		return nil
+	}
+
+	if fd.Message() != nil {
+		return copyMessage(field, value.Interface(), options)
+	}
+
+	if convertible(value.Type(), field.Type()) {
+		field.Set(value.Convert(field.Type()))
+	}
+
+	return nil
+}
+
+// copyMessage copies the given message to the given struct or struct pointer
+// field.
+func copyMessage(field reflect.Value, msg interface{}, options deepcopier.Options) error {
+	switch {
+	case field.Kind() == reflect.Struct:
+		return deepcopier.CopyNested(field.Addr().Interface(), msg, options)
+	case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct:
+		ptr := reflect.New(field.Type().Elem())
+		if err := deepcopier.CopyNested(ptr.Interface(), msg, options); err != nil {
+			return err
+		}
+		field.Set(ptr)
+	}
+
+	return nil
+}
+
+// toOneofs sets oneof fields of the destination message from the first
+// non-zero field of the source struct named after one of their cases.
+func toOneofs(dst protoreflect.Message, src reflect.Value, options deepcopier.Options) error {
+	oneofs := dst.Descriptor().Oneofs()
+
+	for i := 0; i < oneofs.Len(); i++ {
+		fields := oneofs.Get(i).Fields()
+
+		for j := 0; j < fields.Len(); j++ {
+			var (
+				fd    = fields.Get(j)
+				name  = goCamelCase(string(fd.Name()))
+				field = src.FieldByName(name)
+			)
+
+			if !field.IsValid() || field.IsZero() {
+				continue
+			}
+
+			v, ok, err := toValue(dst, fd, field, options)
+			if err != nil {
+				return &deepcopier.FieldError{Path: name, Err: err}
+			}
+
+			if ok {
+				dst.Set(fd, v)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// toValue returns the protobuf value of the given field.
+func toValue(dst protoreflect.Message, fd protoreflect.FieldDescriptor, field reflect.Value, options deepcopier.Options) (protoreflect.Value, bool, error) {
+	if fd.Message() != nil {
+		msg := dst.NewField(fd).Message().Interface()
+
+		if src, ok := field.Interface().(proto.Message); ok && src.ProtoReflect().Descriptor().FullName() == fd.Message().FullName() {
+			return protoreflect.ValueOfMessage(proto.Clone(src).ProtoReflect()), true, nil
+		}
+
+		if converted, ok := (extension{}).Convert(field, reflect.TypeOf(msg)); ok {
+			return protoreflect.ValueOfMessage(converted.Interface().(proto.Message).ProtoReflect()), true, nil
+		}
+
+		if err := deepcopier.CopyNested(msg, field.Interface(), options); err != nil {
+			return protoreflect.Value{}, false, err
+		}
+
+		return protoreflect.ValueOfMessage(msg.ProtoReflect()), true, nil
+	}
+
+	if fd.Enum() != nil {
+		if !isNumber(field.Type()) {
+			return protoreflect.Value{}, false, nil
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(field.Convert(reflect.TypeOf(int32(0))).Int())), true, nil
+	}
+
+	t := scalarType(fd.Kind())
+	if t == nil || !convertible(field.Type(), t) {
+		return protoreflect.Value{}, false, nil
+	}
+
+	return protoreflect.ValueOf(field.Convert(t).Interface()), true, nil
+}
+
+// scalarType returns the Go type of the given scalar kind.
+func scalarType(kind protoreflect.Kind) reflect.Type {
+	switch kind {
+	case protoreflect.BoolKind:
+		return reflect.TypeOf(false)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return reflect.TypeOf(int32(0))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.TypeOf(uint32(0))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return reflect.TypeOf(int64(0))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return reflect.TypeOf(uint64(0))
+	case protoreflect.FloatKind:
+		return reflect.TypeOf(float32(0))
+	case protoreflect.DoubleKind:
+		return reflect.TypeOf(float64(0))
+	case protoreflect.StringKind:
+		return reflect.TypeOf("")
+	case protoreflect.BytesKind:
+		return reflect.TypeOf([]byte(nil))
+	}
+	return nil
+}
+
+// goCamelCase returns the Go name of the given protobuf field name, as
+// generated by protoc-gen-go.
+func goCamelCase(s string) string {
+	var b []byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '.' in ".{{lowercase}}"
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			// Convert initial '_' to ensure we start with a capital letter
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip over '_' in "_{{lowercase}}"
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			// Assume we have a letter now - if not, it's a bogus identifier
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+
+			// Accept lower case sequence that follows
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,175 @@
+package protobuf_test
+
+import (
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/ulule/deepcopier"
+	"github.com/ulule/deepcopier/protobuf"
+)
+
+func init() {
+	deepcopier.Use(protobuf.Extension())
+}
+
+// Event looks like a message generated by an older protoc-gen-go.
+type Event struct {
+	Name                 *wrapperspb.StringValue
+	Count                *wrapperspb.Int64Value
+	Enabled              *wrapperspb.BoolValue
+	CreatedAt            *timestamppb.Timestamp
+	Timeout              *durationpb.Duration
+	XXX_NoUnkeyedLiteral struct{}
+	XXX_unrecognized     []byte
+	XXX_sizecache        int32
+}
+
+func (*Event) Reset()         {}
+func (*Event) String() string { return "" }
+func (*Event) ProtoMessage()  {}
+
+type EventResource struct {
+	Name             *string
+	Count            int
+	Enabled          bool
+	CreatedAt        time.Time
+	Timeout          time.Duration
+	XXX_unrecognized []byte
+}
+
+func TestWellKnownTypes(t *testing.T) {
+	createdAt := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	event := &Event{
+		Name:             wrapperspb.String("launch"),
+		Count:            wrapperspb.Int64(3),
+		CreatedAt:        timestamppb.New(createdAt),
+		Timeout:          durationpb.New(time.Minute),
+		XXX_unrecognized: []byte("internal"),
+	}
+
+	//
+	// To()
+	//
+
+	resource := &EventResource{Enabled: true}
+	assert.Nil(t, deepcopier.Copy(event).To(resource))
+	assert.Equal(t, "launch", *resource.Name)
+	assert.Equal(t, 3, resource.Count)
+	assert.False(t, resource.Enabled)
+	assert.True(t, createdAt.Equal(resource.CreatedAt))
+	assert.Equal(t, time.Minute, resource.Timeout)
+	assert.Nil(t, resource.XXX_unrecognized)
+
+	//
+	// From()
+	//
+
+	name := "landing"
+
+	event = &Event{}
+	assert.Nil(t, deepcopier.Copy(event).From(&EventResource{
+		Name:             &name,
+		Count:            5,
+		Timeout:          time.Second,
+		XXX_unrecognized: []byte("internal"),
+	}))
+	assert.Equal(t, "landing", event.Name.GetValue())
+	assert.Equal(t, int64(5), event.Count.GetValue())
+	assert.False(t, event.Enabled.GetValue())
+	assert.Nil(t, event.CreatedAt)
+	assert.Equal(t, time.Second, event.Timeout.AsDuration())
+	assert.Nil(t, event.XXX_unrecognized)
+}
+
+type Value struct {
+	NumberValue float64
+	StringValue string
+	BoolValue   bool
+	StructValue map[string]interface{}
+}
+
+func TestOneof(t *testing.T) {
+	//
+	// Message -> struct
+	//
+
+	value := &Value{}
+	assert.Nil(t, deepcopier.Copy(structpb.NewStringValue("foo")).To(value))
+	assert.Equal(t, &Value{StringValue: "foo"}, value)
+
+	value = &Value{}
+	assert.Nil(t, deepcopier.Copy(structpb.NewNumberValue(1.5)).To(value))
+	assert.Equal(t, 1.5, value.NumberValue)
+
+	//
+	// Struct -> message
+	//
+
+	msg := &structpb.Value{}
+	assert.Nil(t, deepcopier.Copy(&Value{BoolValue: true}).To(msg))
+	assert.True(t, msg.GetBoolValue())
+
+	msg = &structpb.Value{}
+	assert.Nil(t, deepcopier.Copy(msg).From(&Value{StringValue: "bar"}))
+	assert.Equal(t, "bar", msg.GetStringValue())
+
+	//
+	// Message -> message
+	//
+
+	msg = structpb.NewBoolValue(true)
+	assert.Nil(t, deepcopier.Copy(structpb.NewStringValue("baz")).To(msg))
+	assert.Equal(t, "baz", msg.GetStringValue())
+
+	src, err := structpb.NewValue(map[string]interface{}{"foo": "bar"})
+	assert.Nil(t, err)
+
+	msg = &structpb.Value{}
+	assert.Nil(t, deepcopier.Copy(src).To(msg))
+	assert.Equal(t, "bar", msg.GetStructValue().GetFields()["foo"].GetStringValue())
+	assert.False(t, msg.GetStructValue() == src.GetStructValue())
+}
+
+type Document struct {
+	StructValue *Object
+}
+
+type Object struct {
+	Entries map[string]interface{} `deepcopier:"field:Fields"`
+}
+
+type fieldRecorder struct {
+	fields []string
+}
+
+func (r *fieldRecorder) OnCopyStart(dst interface{}, src interface{}) {}
+
+func (r *fieldRecorder) OnField(path string, copied bool) {
+	r.fields = append(r.fields, path)
+}
+
+func (r *fieldRecorder) OnCopyEnd(dst interface{}, src interface{}, duration time.Duration, err error) {
+}
+
+func TestOneof_Options(t *testing.T) {
+	// Messages of oneof cases are copied with the options of the copy:
+	// struct tags are read from the source of From() and the observer is
+	// notified of their fields.
+	var (
+		recorder = &fieldRecorder{}
+		msg      = &structpb.Value{}
+	)
+
+	assert.Nil(t, deepcopier.Copy(msg).WithObserver(recorder).From(&Document{
+		StructValue: &Object{Entries: map[string]interface{}{"foo": "bar"}},
+	}))
+	assert.NotNil(t, msg.GetStructValue())
+	assert.Contains(t, recorder.fields, "Fields")
+}
@@ -0,0 +1,117 @@
+package deepcopier
+
+import (
+	"context"
+	"iter"
+	"reflect"
+)
+
+// Result is a copy sent by MapChan.
+type Result[T any] struct {
+	// Value is the copy.
+	Value T
+	// Err is the copy error.
+	Err error
+}
+
+// MapSeq returns a sequence of copies of the given sources into new Dst
+// values, with the copy error of each one. Dst can be a struct or a pointer to
+// a struct. Nil sources are copied to zero Dst values. The mapping of the types
+// is compiled once and reused for all sources.
+func MapSeq[Src, Dst any](seq iter.Seq[Src], args ...Options) iter.Seq2[Dst, error] {
+	options := streamOptions[Src, Dst](args)
+
+	return func(yield func(Dst, error) bool) {
+		for src := range seq {
+			if !yield(mapValue[Src, Dst](src, options)) {
+				return
+			}
+		}
+	}
+}
+
+// MapChan copies sources received from the given channel into new Dst values
+// sent to the returned channel, which is closed once the given channel is
+// closed or the context is done. Dst can be a struct or a pointer to a struct.
+// Nil sources are copied to zero Dst values.
+func MapChan[Src, Dst any](ctx context.Context, ch <-chan Src, args ...Options) <-chan Result[Dst] {
+	var (
+		options = streamOptions[Src, Dst](args)
+		out     = make(chan Result[Dst])
+	)
+
+	go func() {
+		defer close(out)
+
+		for {
+			var (
+				src Src
+				ok  bool
+			)
+
+			select {
+			case <-ctx.Done():
+				return
+			case src, ok = <-ch:
+				if !ok {
+					return
+				}
+			}
+
+			value, err := mapValue[Src, Dst](src, options)
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Result[Dst]{Value: value, Err: err}:
+			}
+		}
+	}()
+
+	return out
+}
+
+// streamOptions returns the options of a stream, compiling the mapping of its
+// types before the first copy.
+func streamOptions[Src, Dst any](args []Options) Options {
+	var options Options
+	if len(args) > 0 {
+		options = args[0]
+	}
+
+	var (
+		src = reflect.TypeOf((*Src)(nil)).Elem()
+		dst = reflect.TypeOf((*Dst)(nil)).Elem()
+	)
+
+	getCompiledPlan(src, dst, options.Reversed)
+
+	return options
+}
+
+// mapValue copies the given source into a new Dst value, or returns a zero
+// Dst value if the source is nil.
+func mapValue[Src, Dst any](src Src, options Options) (Dst, error) {
+	var (
+		dst Dst
+		t   = reflect.TypeOf(&dst).Elem()
+	)
+
+	if isNil(reflect.ValueOf(&src).Elem()) {
+		return dst, nil
+	}
+
+	if t.Kind() != reflect.Ptr {
+		err := observe(&dst, src, options, func() error {
+			return process(&dst, src, options)
+		})
+		return dst, err
+	}
+
+	ptr := reflect.New(t.Elem()).Interface()
+	err := observe(ptr, src, options, func() error {
+		return process(ptr, src, options)
+	})
+
+	return ptr.(Dst), err
+}
@@ -0,0 +1,298 @@
+package deepcopier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTag is returned when a struct tag cannot be parsed.
+	ErrInvalidTag = errors.New("invalid tag")
+	// ErrUnknownOption is returned when a struct tag contains an unknown option.
+	ErrUnknownOption = errors.New("unknown option")
+	// ErrDuplicatedOption is returned when a struct tag contains an option twice.
+	ErrDuplicatedOption = errors.New("duplicated option")
+)
+
+// tagOptionNames are the options known by ParseTag.
+var tagOptionNames = map[string]bool{
+	FieldOptionName:       true,
+	ContextOptionName:     true,
+	SkipOptionName:        true,
+	ForceOptionName:       true,
+	SetterOptionName:      true,
+	DefaultOptionName:     true,
+	FromContextOptionName: true,
+	ReadOnlyOptionName:    true,
+	WriteOnlyOptionName:   true,
+	ToOptionName:          true,
+	FromOptionName:        true,
+	LayoutOptionName:      true,
+	TimeZoneOptionName:    true,
+	MergeKeyOptionName:    true,
+	OrphanOptionName:      true,
+	IfOptionName:          true,
+}
+
+// TagError is returned in strict mode when a deepcopier struct tag is invalid.
+type TagError struct {
+	// Type is the struct type.
+	Type reflect.Type
+	// Field is the field name.
+	Field string
+	// Tag is the deepcopier struct tag value.
+	Tag string
+	// Err is the parsing error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TagError) Error() string {
+	return fmt.Sprintf("invalid deepcopier tag of %s.%s: %s", e.Type.Name(), e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// ParseTag parses the given deepcopier struct tag value.
+//
+// Options are separated by semicolons and values are separated from option
+// names by the first colon: "field:Name; force". Spaces around names and values
+// are trimmed. Values can be single-quoted ('a;b') and a backslash escapes the
+// following character.
+//
+// Options are returned even when an error occurs, in which case the first error
+// is returned: unknown and duplicated options are reported but kept (the last
+// value wins).
+func ParseTag(tag string) (TagOptions, error) {
+	var (
+		options = TagOptions{}
+		first   error
+		s       = &tagScanner{input: tag}
+	)
+
+	for !s.done() {
+		key, value, hasValue, err := s.next()
+		if err != nil && first == nil {
+			first = err
+		}
+
+		if key == "" {
+			if hasValue && first == nil {
+				first = fmt.Errorf("%w: missing option name before %q", ErrInvalidTag, value)
+			}
+			continue
+		}
+
+		if _, ok := options[key]; ok && first == nil {
+			first = fmt.Errorf("%w %q", ErrDuplicatedOption, key)
+		}
+
+		if !tagOptionNames[key] && first == nil {
+			first = fmt.Errorf("%w %q", ErrUnknownOption, key)
+		}
+
+		options[key] = value
+	}
+
+	return options, first
+}
+
+// List returns the comma-separated values of the given option.
+func (o TagOptions) List(key string) []string {
+	value, ok := o[key]
+	if !ok || value == "" {
+		return nil
+	}
+
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
+
+// tagScanner reads options of a deepcopier struct tag.
+type tagScanner struct {
+	input string
+	pos   int
+}
+
+// done returns true if the whole input has been read.
+func (s *tagScanner) done() bool {
+	return s.pos >= len(s.input)
+}
+
+// next reads the next option.
+func (s *tagScanner) next() (string, string, bool, error) {
+	start := s.pos
+	for !s.done() && s.input[s.pos] != ':' && s.input[s.pos] != ';' {
+		s.pos++
+	}
+
+	key := strings.TrimSpace(s.input[start:s.pos])
+
+	if s.done() || s.input[s.pos] == ';' {
+		s.pos++
+		return key, "", false, nil
+	}
+
+	// Skip colon and leading spaces
+	s.pos++
+	for !s.done() && s.input[s.pos] == ' ' {
+		s.pos++
+	}
+
+	if !s.done() && s.input[s.pos] == '\'' {
+		value, err := s.quoted()
+		return key, value, true, err
+	}
+
+	return key, s.unquoted(), true, nil
+}
+
+// unquoted reads a value until the next unescaped semicolon.
+func (s *tagScanner) unquoted() string {
+	var b strings.Builder
+
+	for ; !s.done(); s.pos++ {
+		c := s.input[s.pos]
+
+		if c == ';' {
+			s.pos++
+			break
+		}
+
+		if c == '\\' && s.pos+1 < len(s.input) {
+			s.pos++
+			c = s.input[s.pos]
+		}
+
+		b.WriteByte(c)
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// quoted reads a single-quoted value and the following semicolon.
+func (s *tagScanner) quoted() (string, error) {
+	var (
+		b      strings.Builder
+		closed bool
+	)
+
+	for s.pos++; !s.done(); s.pos++ {
+		c := s.input[s.pos]
+
+		if c == '\'' {
+			closed = true
+			s.pos++
+			break
+		}
+
+		if c == '\\' && s.pos+1 < len(s.input) {
+			s.pos++
+			c = s.input[s.pos]
+		}
+
+		b.WriteByte(c)
+	}
+
+	if !closed {
+		return b.String(), fmt.Errorf("%w: unterminated quoted value", ErrInvalidTag)
+	}
+
+	rest := s.pos
+	for !s.done() && s.input[s.pos] != ';' {
+		s.pos++
+	}
+
+	trailing := strings.TrimSpace(s.input[rest:s.pos])
+	s.pos++
+
+	if trailing != "" {
+		return b.String(), fmt.Errorf("%w: unexpected %q after quoted value", ErrInvalidTag, trailing)
+	}
+
+	return b.String(), nil
+}
+
+// checkTags returns the first invalid deepcopier struct tag of the given type,
+// including embedded structs.
+func checkTags(t reflect.Type) error {
+	errs := getTagErrors(t)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// getTagErrors returns errors of invalid deepcopier struct tags of the given
+// type, including embedded structs.
+func getTagErrors(t reflect.Type) []error {
+	var errs []error
+
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			errs = append(errs, getTagErrors(f.Type)...)
+		}
+
+		tag, ok := f.Tag.Lookup(TagName)
+		if !ok {
+			continue
+		}
+
+		options, err := ParseTag(tag)
+		if err == nil {
+			if value, ok := options[DefaultOptionName]; ok {
+				_, err = parseDefault(value, f.Type)
+			}
+		}
+
+		if value := options[TimeZoneOptionName]; err == nil && value != "" {
+			if _, lerr := time.LoadLocation(value); lerr != nil {
+				err = fmt.Errorf("%w: unknown time zone %q", ErrInvalidTag, value)
+			}
+		}
+
+		if value, ok := options[OrphanOptionName]; err == nil && ok && value != OrphanKeep && value != OrphanDelete {
+			err = fmt.Errorf("%w: orphan option must be %q or %q", ErrInvalidTag, OrphanKeep, OrphanDelete)
+		}
+
+		if _, ok := options[MergeKeyOptionName]; err == nil && ok && (f.Type.Kind() != reflect.Slice || options[MergeKeyOptionName] == "") {
+			err = fmt.Errorf("%w: mergekey option requires a key and a slice field", ErrInvalidTag)
+		}
+
+		if value, ok := options[IfOptionName]; err == nil && ok && value == "" {
+			err = fmt.Errorf("%w: if option requires a condition", ErrInvalidTag)
+		}
+
+		if err == nil {
+			_, readOnly := options[ReadOnlyOptionName]
+			_, writeOnly := options[WriteOnlyOptionName]
+
+			if readOnly && writeOnly {
+				err = fmt.Errorf("%w: readonly and writeonly options are exclusive", ErrInvalidTag)
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, &TagError{Type: t, Field: f.Name, Tag: tag, Err: err})
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,247 @@
+package deepcopier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnregisteredType is returned when a struct is copied to an interface
+// implemented by registered targets but none is registered for its type.
+var ErrUnregisteredType = errors.New("unregistered type")
+
+// TargetOptions are options of a registered target.
+type TargetOptions struct {
+	// Discriminator is the name of a destination field set to Value once a
+	// source is copied to the target.
+	Discriminator string
+	// Value is the value of the discriminator field, converted to its type.
+	Value interface{}
+}
+
+// target is a registered target type.
+type target struct {
+	t             reflect.Type
+	discriminator string
+	value         reflect.Value
+}
+
+var (
+	targetsMu sync.RWMutex
+	targets   = map[reflect.Type][]target{}
+)
+
+// RegisterTarget registers Dst as the concrete type of copies of Src values
+// into interface destinations, fields or elements of slices and maps, which
+// are otherwise only assigned with the force option. A new Dst is copied from
+// the source and set to the destination if *Dst implements its interface.
+// Several targets can be registered for a source type, the first one
+// implementing the destination interface is used.
+//
+// Once a target implements an interface, copying a struct of another type to
+// this interface returns ErrUnregisteredType.
+//
+// RegisterTarget panics if Src is not a struct or a pointer to a struct, if
+// Dst is not a struct or if the discriminator field cannot be set to its
+// value.
+func RegisterTarget[Src, Dst any](args ...TargetOptions) {
+	var (
+		src     = indirectType(reflect.TypeOf((*Src)(nil)).Elem())
+		dst     = reflect.TypeOf((*Dst)(nil)).Elem()
+		options TargetOptions
+	)
+
+	if len(args) > 0 {
+		options = args[0]
+	}
+
+	if src.Kind() != reflect.Struct || dst.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("deepcopier: cannot register target %s of %s: types must be structs", dst, src))
+	}
+
+	tg := target{t: dst, discriminator: options.Discriminator}
+
+	if tg.discriminator != "" {
+		field, ok := dst.FieldByName(tg.discriminator)
+		if !ok || field.PkgPath != "" {
+			panic(fmt.Sprintf("deepcopier: unknown discriminator field %s in target %s", tg.discriminator, dst))
+		}
+
+		if tg.value, ok = convert(reflect.ValueOf(options.Value), field.Type); !ok {
+			panic(fmt.Sprintf("deepcopier: cannot use discriminator value %v as %s", options.Value, field.Type))
+		}
+	}
+
+	targetsMu.Lock()
+	defer targetsMu.Unlock()
+
+	for i, t := range targets[src] {
+		if t.t == dst {
+			targets[src][i] = tg
+			return
+		}
+	}
+
+	targets[src] = append(targets[src], tg)
+}
+
+// getTarget returns the registered target of the given source type whose
+// pointer implements the given interface.
+func getTarget(src reflect.Type, iface reflect.Type) (target, bool) {
+	targetsMu.RLock()
+	defer targetsMu.RUnlock()
+
+	for _, t := range targets[indirectType(src)] {
+		if reflect.PtrTo(t.t).Implements(iface) {
+			return t, true
+		}
+	}
+
+	return target{}, false
+}
+
+// hasTarget returns true if values of the given source type can be copied to
+// a registered target implementing the given interface. All targets are
+// considered for interface sources.
+func hasTarget(src reflect.Type, iface reflect.Type) bool {
+	if src.Kind() != reflect.Interface {
+		_, ok := getTarget(src, iface)
+		return ok
+	}
+
+	return isPolymorphic(iface)
+}
+
+// isPolymorphic returns true if the given interface is not empty and is
+// implemented by registered targets.
+func isPolymorphic(iface reflect.Type) bool {
+	if iface.NumMethod() == 0 {
+		return false
+	}
+
+	targetsMu.RLock()
+	defer targetsMu.RUnlock()
+
+	for _, types := range targets {
+		for _, t := range types {
+			if reflect.PtrTo(t.t).Implements(iface) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// copyInterface copies the given source into the given interface destination
+// with a registered target. Without target, the source is assigned with the
+// force option, and structs copied to polymorphic interfaces return
+// ErrUnregisteredType. It returns false if the destination is not set.
+func copyInterface(dst reflect.Value, name string, src reflect.Value, force bool, options Options) (bool, error) {
+	if options.maxDepthReached() {
+		return false, nil
+	}
+
+	v, ok, err := copyTarget(dst.Type(), src, options.withPath(name))
+	if err != nil {
+		return false, err
+	}
+
+	if ok {
+		if !v.IsValid() {
+			return false, nil
+		}
+
+		setField(dst, name, options, v)
+		return true, nil
+	}
+
+	if force {
+		if !src.Type().AssignableTo(dst.Type()) {
+			return false, nil
+		}
+
+		setField(dst, name, options, src)
+		return true, nil
+	}
+
+	if v := dynamicValue(src); v.IsValid() && indirectType(v.Type()).Kind() == reflect.Struct && !isNil(v) && isPolymorphic(dst.Type()) {
+		return false, fmt.Errorf("%w %s", ErrUnregisteredType, v.Type())
+	}
+
+	return false, nil
+}
+
+// copyTarget copies the dynamic value of the given source into a pointer to a
+// new registered target implementing the given interface type. It returns false
+// if the source is nil or no registered target implements the interface, and
+// an invalid value if the source is skipped by the cycle policy.
+func copyTarget(iface reflect.Type, src reflect.Value, options Options) (reflect.Value, bool, error) {
+	src = dynamicValue(src)
+	if !src.IsValid() || isNil(src) {
+		return reflect.Value{}, false, nil
+	}
+
+	t, ok := getTarget(src.Type(), iface)
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+
+	if v, found, err := checkCycle(src, reflect.PtrTo(t.t), options); found || err != nil {
+		return v, true, err
+	}
+
+	ptr := reflect.New(t.t)
+	if err := process(ptr.Interface(), src.Interface(), options); err != nil {
+		return reflect.Value{}, true, err
+	}
+
+	if t.discriminator != "" {
+		setField(ptr.Elem().FieldByName(t.discriminator), t.discriminator, options.withoutChanges(), t.value)
+	}
+
+	return ptr, true, nil
+}
+
+// copyDynamic copies the dynamic value of the given interface source into
+// the given field of a concrete type. The value is assigned, dereferenced or
+// copied field by field like values of concrete fields. It returns false if
+// the value is not copied.
+func copyDynamic(dst reflect.Value, name string, src reflect.Value, options Options) (bool, error) {
+	v := dynamicValue(src)
+	if !v.IsValid() {
+		return false, nil
+	}
+
+	switch {
+	case v.Type().AssignableTo(dst.Type()):
+		setField(dst, name, options, v)
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Type().AssignableTo(dst.Type()):
+		setField(dst, name, options, v.Elem())
+	case isCollection(v.Type(), dst.Type()):
+		if options.maxDepthReached() {
+			return false, nil
+		}
+
+		c, err := copyCollection(dst.Type(), v, options.withPath(name))
+		if err != nil {
+			return false, err
+		}
+
+		setField(dst, name, options, c)
+	default:
+		return copyNested(dst, v, options.withPath(name))
+	}
+
+	return true, nil
+}
+
+// dynamicValue returns the value held by the given interface value, or the
+// value itself if it is not an interface.
+func dynamicValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type FlatUser struct {
+	ID        int
+	Name      string
+	Email     string
+	Score     float64
+	Active    bool
+	Age       uint8
+	CreatedAt time.Time
+}
+
+type FlatUserResource struct {
+	ID       int
+	Name     string
+	Mail     string `deepcopier:"field:Email"`
+	Score    float64
+	Active   bool
+	Age      uint8
+	Password string `deepcopier:"skip"`
+}
+
+type MethodUser struct {
+	FlatUser
+}
+
+func (u *MethodUser) DisplayName() string {
+	return u.Name + " <" + u.Email + ">"
+}
+
+type MethodUserResource struct {
+	FlatUserResource
+	DisplayName string
+}
+
+func flatUser() *FlatUser {
+	return &FlatUser{ID: 1, Name: "foo", Email: "foo@example.com", Score: 4.5, Active: true, Age: 42}
+}
+
+func TestFlat_Allocs(t *testing.T) {
+	var (
+		src = flatUser()
+		dst = &FlatUserResource{}
+	)
+
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, FlatUserResource{ID: 1, Name: "foo", Mail: "foo@example.com", Score: 4.5, Active: true, Age: 42}, *dst)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := deepcopier.Copy(src).To(dst); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+
+	allocs = testing.AllocsPerRun(100, func() {
+		if err := deepcopier.Copy(dst).From(src); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkCopy_Flat(b *testing.B) {
+	var (
+		src = flatUser()
+		dst = &FlatUserResource{}
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := deepcopier.Copy(src).To(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopy_Methods(b *testing.B) {
+	var (
+		src = &MethodUser{FlatUser: *flatUser()}
+		dst = &MethodUserResource{}
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := deepcopier.Copy(src).To(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCopy_Nested(b *testing.B) {
+	var (
+		src = &CollectionTable{Rows: rows(10)}
+		dst = &CollectionTableResource{}
+	)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := deepcopier.Copy(src).To(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type CollectionRow struct {
+	ID   int
+	Name string
+}
+
+type CollectionRowResource struct {
+	ID    int
+	Label string `deepcopier:"field:Name"`
+}
+
+var errInvalidRow = errors.New("invalid row")
+
+// Validate implements deepcopier.Validator.
+func (r *CollectionRowResource) Validate() error {
+	if r.ID < 0 {
+		return errInvalidRow
+	}
+	return nil
+}
+
+type CollectionTable struct {
+	Rows    []CollectionRow
+	PtrRows []*CollectionRow
+	ByName  map[string]CollectionRow
+	Nil     []CollectionRow
+}
+
+type CollectionTableResource struct {
+	Rows    []CollectionRowResource
+	PtrRows []*CollectionRowResource
+	ByName  map[string]*CollectionRowResource
+	Nil     []CollectionRowResource
+}
+
+func rows(n int) []CollectionRow {
+	rows := make([]CollectionRow, n)
+	for i := range rows {
+		rows[i] = CollectionRow{ID: i, Name: fmt.Sprintf("row-%d", i)}
+	}
+	return rows
+}
+
+func TestCollection(t *testing.T) {
+	table := &CollectionTable{
+		Rows:    rows(3),
+		PtrRows: []*CollectionRow{{ID: 1, Name: "foo"}, nil},
+		ByName:  map[string]CollectionRow{"bar": {ID: 2, Name: "bar"}},
+	}
+
+	resource := &CollectionTableResource{Nil: []CollectionRowResource{{ID: 1}}}
+	assert.Nil(t, deepcopier.Copy(table).To(resource))
+	assert.Equal(t, []CollectionRowResource{{0, "row-0"}, {1, "row-1"}, {2, "row-2"}}, resource.Rows)
+	assert.Equal(t, []*CollectionRowResource{{ID: 1, Label: "foo"}, nil}, resource.PtrRows)
+	assert.Equal(t, map[string]*CollectionRowResource{"bar": {ID: 2, Label: "bar"}}, resource.ByName)
+	assert.Nil(t, resource.Nil)
+
+	//
+	// Top-level
+	//
+
+	var resources []CollectionRowResource
+	assert.Nil(t, deepcopier.Copy(rows(2)).To(&resources))
+	assert.Equal(t, []CollectionRowResource{{0, "row-0"}, {1, "row-1"}}, resources)
+
+	plan := deepcopier.Explain(reflect.TypeOf(CollectionTable{}), reflect.TypeOf(CollectionTableResource{}))
+	m, ok := plan.Mapping("Rows")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionCollection, m.Conversion)
+
+	m, ok = m.Nested.Mapping("Label")
+	assert.True(t, ok)
+	assert.Equal(t, "Name", m.Source)
+}
+
+func TestCollection_Parallel(t *testing.T) {
+	table := &CollectionTable{
+		Rows:    rows(10000),
+		PtrRows: []*CollectionRow{{ID: 1}},
+		ByName:  map[string]CollectionRow{},
+	}
+
+	for _, row := range table.Rows[:100] {
+		table.ByName[row.Name] = row
+	}
+
+	var changes []deepcopier.Change
+
+	resource := &CollectionTableResource{}
+	assert.Nil(t, deepcopier.Copy(table).Parallel(8).WithChangeLog(&changes).To(resource))
+	assert.Len(t, resource.Rows, 10000)
+	assert.Len(t, resource.ByName, 100)
+	assert.Len(t, changes, 3)
+
+	for i, row := range resource.Rows {
+		assert.Equal(t, CollectionRowResource{ID: i, Label: fmt.Sprintf("row-%d", i)}, row)
+	}
+
+	for name, row := range resource.ByName {
+		assert.Equal(t, name, row.Label)
+	}
+
+	var resources []*CollectionRowResource
+	assert.Nil(t, deepcopier.Copy(table.Rows).Parallel(4).To(&resources))
+	assert.Len(t, resources, 10000)
+	assert.Equal(t, &CollectionRowResource{ID: 9999, Label: "row-9999"}, resources[9999])
+}
+
+func TestCollection_Errors(t *testing.T) {
+	src := rows(100)
+	src[10].ID = -1
+	src[20].ID = -1
+
+	for _, n := range []int{0, 8} {
+		var dst []CollectionRowResource
+
+		err := deepcopier.Copy(src).Parallel(n).To(&dst)
+		assert.True(t, errors.Is(err, errInvalidRow))
+
+		var fieldErr *deepcopier.FieldError
+		assert.True(t, errors.As(err, &fieldErr))
+		assert.Contains(t, []string{"10", "20"}, fieldErr.Path)
+
+		err = deepcopier.Copy(&CollectionTable{Rows: src}).Parallel(n).Strict().To(&CollectionTableResource{})
+		assert.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, "Rows", fieldErr.Path)
+
+		var errs deepcopier.Errors
+		assert.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 2)
+		assert.Equal(t, "10: validation failed: invalid row", errs[0].Error())
+		assert.Equal(t, "20: validation failed: invalid row", errs[1].Error())
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type ConditionUser struct {
+	Name   string
+	Email  string
+	Phone  string
+	Public bool
+}
+
+func (u *ConditionUser) IsPublic() bool {
+	return u.Public
+}
+
+func (u *ConditionUser) IsVisible(ctx map[string]interface{}) bool {
+	return u.Public || ctx["is_owner"] == true
+}
+
+func (u *ConditionUser) Contact() string {
+	return u.Email
+}
+
+type ConditionUserResource struct {
+	Name    string `deepcopier:"if:is_admin"`
+	Email   string `deepcopier:"if:IsPublic"`
+	Phone   string `deepcopier:"if:IsVisible"`
+	Contact string `deepcopier:"if:IsPublic"`
+}
+
+func TestCondition(t *testing.T) {
+	deepcopier.RegisterPredicate("is_admin", func(src interface{}, ctx deepcopier.Context) bool {
+		return ctx.Values["is_admin"] == true
+	})
+
+	user := &ConditionUser{Name: "gilles", Email: "gilles@example.com", Phone: "0102030405"}
+
+	resource := &ConditionUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).To(resource))
+	assert.Equal(t, &ConditionUserResource{}, resource)
+
+	// Methods and predicates taking the context
+	resource = &ConditionUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).WithContext(map[string]interface{}{"is_owner": true, "is_admin": true}).To(resource))
+	assert.Equal(t, &ConditionUserResource{Name: "gilles", Phone: "0102030405"}, resource)
+
+	// Source methods
+	user.Public = true
+	resource = &ConditionUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).To(resource))
+	assert.Equal(t, &ConditionUserResource{Email: "gilles@example.com", Phone: "0102030405", Contact: "gilles@example.com"}, resource)
+
+	// Skipped fields are observed
+	observer := &recordingObserver{}
+	assert.Nil(t, deepcopier.Copy(user).WithObserver(observer).To(&ConditionUserResource{}))
+	assert.Equal(t, []string{"Name"}, observer.skipped)
+}
+
+func TestCondition_Errors(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name string `deepcopier:"if:unknown"`
+		}
+
+		Invalid struct {
+			Name string `deepcopier:"if:"`
+		}
+	)
+
+	err := deepcopier.Copy(&Src{Name: "gilles"}).To(&Dst{})
+	assert.True(t, errors.Is(err, deepcopier.ErrUnknownCondition))
+	assert.Equal(t, `Name: unknown condition "unknown"`, err.Error())
+
+	err = deepcopier.Copy(&Src{}).Strict().To(&Invalid{})
+	assert.True(t, errors.Is(err, deepcopier.ErrInvalidTag))
+}
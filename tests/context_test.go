@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestFromContext(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name    string
+			BaseURL string `deepcopier:"fromcontext:base_url"`
+			Limit   int64  `deepcopier:"fromcontext:limit"`
+			UserID  *int   `deepcopier:"fromcontext:user_id"`
+			Missing string `deepcopier:"fromcontext:missing"`
+		}
+	)
+
+	//
+	// To()
+	//
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{Name: "gilles"}).WithContext(map[string]interface{}{
+		"base_url": "http://example.com",
+		"limit":    10,
+	}).To(dst))
+	assert.Equal(t, "gilles", dst.Name)
+	assert.Equal(t, "http://example.com", dst.BaseURL)
+	assert.Equal(t, int64(10), dst.Limit)
+	assert.Nil(t, dst.UserID)
+	assert.Empty(t, dst.Missing)
+
+	//
+	// context.Context
+	//
+
+	ctx := context.WithValue(context.Background(), deepcopier.ContextKey("user_id"), 42)
+	ctx = context.WithValue(ctx, deepcopier.ContextKey("base_url"), "http://ignored.com")
+
+	dst = &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{}).
+		WithContext(map[string]interface{}{"base_url": "http://example.com"}).
+		WithStdContext(ctx).
+		To(dst))
+	assert.Equal(t, "http://example.com", dst.BaseURL)
+	assert.Equal(t, 42, *dst.UserID)
+
+	//
+	// From()
+	//
+
+	type Resource struct {
+		BaseURL string `deepcopier:"field:URL; fromcontext:base_url"`
+	}
+
+	type Model struct {
+		URL string
+	}
+
+	model := &Model{}
+	assert.Nil(t, deepcopier.Copy(model).WithContext(map[string]interface{}{"base_url": "http://example.com"}).From(&Resource{}))
+	assert.Equal(t, "http://example.com", model.URL)
+}
+
+func TestFromContext_Strict(t *testing.T) {
+	type (
+		Src struct{}
+
+		Dst struct {
+			BaseURL string `deepcopier:"fromcontext:base_url"`
+			Limit   int    `deepcopier:"fromcontext:limit"`
+		}
+	)
+
+	err := deepcopier.Copy(&Src{}).Strict().WithContext(map[string]interface{}{"limit": 1}).To(&Dst{})
+	assert.True(t, errors.Is(err, deepcopier.ErrMissingContextKey))
+
+	var fieldErr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "BaseURL", fieldErr.Path)
+
+	err = deepcopier.Copy(&Src{}).Strict().WithContext(map[string]interface{}{
+		"base_url": "http://example.com",
+		"limit":    "ten",
+	}).To(&Dst{})
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, deepcopier.ErrMissingContextKey))
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{}).WithContext(map[string]interface{}{"limit": "ten"}).To(dst))
+	assert.Zero(t, dst.Limit)
+}
+
+func TestFromContext_Explain(t *testing.T) {
+	type (
+		Src struct{}
+
+		Dst struct {
+			BaseURL string `deepcopier:"fromcontext:base_url"`
+		}
+	)
+
+	plan := deepcopier.Explain(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}))
+	m, ok := plan.Mapping("BaseURL")
+	assert.True(t, ok)
+	assert.Equal(t, "base_url", m.FromContext)
+	assert.Empty(t, m.Skipped)
+	assert.Contains(t, plan.String(), "(context: base_url)")
+}
+
+type ContextAuthor struct {
+	Name string
+	Page int
+}
+
+func (a *ContextAuthor) Link(ctx deepcopier.Context) string {
+	return ctx.String("base_url") + "/" + ctx.Path + "/" + ctx.Src.(*ContextAuthor).Name
+}
+
+func (a *ContextAuthor) Followers(ctx map[string]interface{}) int {
+	return ctx["followers"].(int)
+}
+
+type ContextPost struct {
+	Title  string
+	Author *ContextAuthor
+}
+
+type ContextAuthorResource struct {
+	Name      string
+	Link      string `deepcopier:"context"`
+	Followers int    `deepcopier:"context"`
+	Page      int    `deepcopier:"if:page_one"`
+}
+
+type ContextPostResource struct {
+	Title  string
+	Author ContextAuthorResource
+}
+
+func TestContext(t *testing.T) {
+	deepcopier.RegisterPredicate("page_one", func(src interface{}, ctx deepcopier.Context) bool {
+		return ctx.Int("page") == 1 && ctx.Dst.(*ContextAuthorResource).Name == "gilles"
+	})
+
+	var (
+		std  = context.WithValue(context.Background(), deepcopier.ContextKey("page"), 1.0)
+		post = &ContextPost{Title: "foo", Author: &ContextAuthor{Name: "gilles", Page: 3}}
+		dst  = &ContextPostResource{}
+	)
+
+	// Typed context and map context methods
+	assert.Nil(t, deepcopier.Copy(post).
+		WithContext(map[string]interface{}{"base_url": "http://example.com", "followers": 2}).
+		WithStdContext(std).
+		To(dst))
+	assert.Equal(t, "http://example.com/Author.Link/gilles", dst.Author.Link)
+	assert.Equal(t, 2, dst.Author.Followers)
+	assert.Equal(t, 3, dst.Author.Page)
+
+	dst = &ContextPostResource{}
+	assert.Nil(t, deepcopier.Copy(post).WithContext(map[string]interface{}{"followers": 2}).To(dst))
+	assert.Zero(t, dst.Author.Page)
+
+	//
+	// Getters
+	//
+
+	ctx := deepcopier.Context{Values: map[string]interface{}{"name": "gilles", "page": int64(2), "ratio": 1.5}, StdContext: std}
+	assert.Equal(t, "gilles", ctx.String("name"))
+	assert.Equal(t, "", ctx.String("page"))
+	assert.Equal(t, 2, ctx.Int("page"))
+	assert.Equal(t, 0, ctx.Int("ratio"))
+	assert.Equal(t, 0, ctx.Int("name"))
+	assert.Equal(t, 1.5, ctx.Value("ratio"))
+	assert.Nil(t, ctx.Value("missing"))
+
+	// Values missing from the map are looked up in the context.Context
+	ctx.Values = nil
+	assert.Equal(t, 1, ctx.Int("page"))
+	assert.Equal(t, std, ctx.StdContext)
+}
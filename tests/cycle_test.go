@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type CycleNode struct {
+	ID       int
+	Next     *CycleNode
+	Children []*CycleNode
+}
+
+type CycleNodeResource struct {
+	ID       int
+	Next     *CycleNodeResource
+	Children []*CycleNodeResource
+}
+
+type CycleUser struct {
+	Name  string
+	Posts []*CyclePost
+}
+
+type CyclePost struct {
+	Title  string
+	Author *CycleUser
+}
+
+type CycleUserResource struct {
+	Name  string
+	Posts []*CyclePostResource
+}
+
+type CyclePostResource struct {
+	Title  string
+	Author *CycleUserResource
+}
+
+func chain(n int) *CycleNode {
+	var node *CycleNode
+	for i := n; i > 0; i-- {
+		node = &CycleNode{ID: i, Next: node}
+	}
+	return node
+}
+
+func TestCycle_SelfReferential(t *testing.T) {
+	node := &CycleNode{ID: 1}
+	node.Next = node
+
+	err := deepcopier.Copy(node).To(&CycleNodeResource{})
+	assert.True(t, errors.Is(err, deepcopier.ErrCycle))
+	assert.Equal(t, "Next: cycle of *tests.CycleNode", err.Error())
+
+	resource := &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleSkip).To(resource))
+	assert.Equal(t, &CycleNodeResource{ID: 1}, resource)
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleReuse).To(resource))
+	assert.Equal(t, 1, resource.ID)
+	assert.Same(t, resource, resource.Next)
+
+	//
+	// Elements
+	//
+
+	node = &CycleNode{ID: 1, Children: []*CycleNode{{ID: 2}}}
+	node.Children[0].Children = []*CycleNode{node}
+
+	err = deepcopier.Copy(node).To(&CycleNodeResource{})
+	assert.True(t, errors.Is(err, deepcopier.ErrCycle))
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleSkip).To(resource))
+	assert.Equal(t, []*CycleNodeResource{nil}, resource.Children[0].Children)
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleReuse).To(resource))
+	assert.Same(t, resource, resource.Children[0].Children[0])
+}
+
+func TestCycle_MutuallyReferential(t *testing.T) {
+	user := &CycleUser{Name: "gilles"}
+	user.Posts = []*CyclePost{{Title: "foo", Author: user}, {Title: "bar", Author: user}}
+
+	err := deepcopier.Copy(user).To(&CycleUserResource{})
+	assert.True(t, errors.Is(err, deepcopier.ErrCycle))
+	assert.Equal(t, "Posts.0.Author: cycle of *tests.CycleUser", err.Error())
+
+	resource := &CycleUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).OnCycle(deepcopier.CycleSkip).To(resource))
+	assert.Len(t, resource.Posts, 2)
+	assert.Equal(t, &CyclePostResource{Title: "foo"}, resource.Posts[0])
+
+	resource = &CycleUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).OnCycle(deepcopier.CycleReuse).Parallel(2).To(resource))
+	assert.Equal(t, "bar", resource.Posts[1].Title)
+	assert.Same(t, resource, resource.Posts[0].Author)
+	assert.Same(t, resource, resource.Posts[1].Author)
+
+	post := &CyclePostResource{}
+	assert.Nil(t, deepcopier.Copy(user.Posts[0]).OnCycle(deepcopier.CycleReuse).To(post))
+	assert.Same(t, post, post.Author.Posts[0])
+	assert.Equal(t, "bar", post.Author.Posts[1].Title)
+}
+
+func TestCycle_SharedPointers(t *testing.T) {
+	shared := &CycleNode{ID: 2}
+	node := &CycleNode{ID: 1, Next: shared, Children: []*CycleNode{shared}}
+
+	resource := &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).To(resource))
+	assert.Equal(t, resource.Next, resource.Children[0])
+	assert.NotSame(t, resource.Next, resource.Children[0])
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleReuse).To(resource))
+	assert.Same(t, resource.Next, resource.Children[0])
+
+	var resources []*CycleNodeResource
+	assert.Nil(t, deepcopier.Copy([]*CycleNode{shared, shared}).OnCycle(deepcopier.CycleReuse).To(&resources))
+	assert.Same(t, resources[0], resources[1])
+}
+
+func TestMaxDepth(t *testing.T) {
+	resource := &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(chain(4)).MaxDepth(2).To(resource))
+	assert.Equal(t, 2, resource.Next.ID)
+	assert.Nil(t, resource.Next.Next)
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(chain(4)).MaxDepth(1).To(resource))
+	assert.Equal(t, &CycleNodeResource{ID: 1}, resource)
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(chain(4)).To(resource))
+	assert.Equal(t, 4, resource.Next.Next.Next.ID)
+
+	//
+	// Collections and cycles
+	//
+
+	user := &CycleUser{Name: "gilles"}
+	user.Posts = []*CyclePost{{Title: "foo", Author: user}}
+
+	userResource := &CycleUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).MaxDepth(2).To(userResource))
+	assert.Equal(t, []*CyclePostResource{{Title: "foo"}}, userResource.Posts)
+
+	userResource = &CycleUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).MaxDepth(1).To(userResource))
+	assert.Nil(t, userResource.Posts)
+
+	var resources []*CycleNodeResource
+	assert.Nil(t, deepcopier.Copy([]*CycleNode{chain(2)}).MaxDepth(1).To(&resources))
+	assert.Equal(t, []*CycleNodeResource{{ID: 1}}, resources)
+}
+
+func TestCycle_Maps(t *testing.T) {
+	node := &CycleNode{ID: 1}
+	node.Next = node
+
+	//
+	// Struct -> Map
+	//
+
+	err := deepcopier.Copy(node).MaxDepth(3).To(&map[string]interface{}{})
+	assert.True(t, errors.Is(err, deepcopier.ErrCycle))
+	assert.Equal(t, "Next: cycle of *tests.CycleNode", err.Error())
+
+	m := map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleSkip).To(&m))
+	assert.Equal(t, 1, m["ID"])
+	assert.NotContains(t, m, "Next")
+
+	m = map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(node).OnCycle(deepcopier.CycleReuse).To(&m))
+	assert.Equal(t, reflect.ValueOf(m).Pointer(), reflect.ValueOf(m["Next"]).Pointer())
+
+	m = map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(chain(4)).MaxDepth(2).To(&m))
+	assert.Equal(t, 2, m["Next"].(map[string]interface{})["ID"])
+	assert.NotContains(t, m["Next"], "Next")
+
+	//
+	// Map -> Struct
+	//
+
+	src := map[string]interface{}{
+		"ID": 1,
+		"Next": map[string]interface{}{
+			"ID":   2,
+			"Next": map[string]interface{}{"ID": 3},
+		},
+	}
+
+	resource := &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(src).MaxDepth(2).To(resource))
+	assert.Equal(t, 2, resource.Next.ID)
+	assert.Nil(t, resource.Next.Next)
+
+	resource = &CycleNodeResource{}
+	assert.Nil(t, deepcopier.Copy(src).To(resource))
+	assert.Equal(t, 3, resource.Next.Next.ID)
+}
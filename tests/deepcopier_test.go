@@ -2,6 +2,8 @@ package tests
 
 import (
 	"database/sql"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -666,6 +668,63 @@ func TestMethod(t *testing.T) {
 	assert.Equal(t, *MethodTesterFoo{}.FooMapPtrToMap(), dst.FooMapPtrToMap)
 }
 
+func TestMethod_Receivers(t *testing.T) {
+	var (
+		embedded = MethodReceiverEmbedded{Name: "embedded"}
+		value    = MethodReceiverTester{
+			Name:                      "tester",
+			MethodReceiverEmbedded:    embedded,
+			MethodReceiverEmbeddedPtr: &MethodReceiverEmbeddedPtr{Name: "embedded-ptr"},
+		}
+		expected = MethodReceiverResource{
+			ValueReceiver:            "tester",
+			PointerReceiver:          "tester",
+			EmbeddedValueReceiver:    "embedded",
+			EmbeddedPointerReceiver:  "embedded",
+			EmbeddedPtrValueReceiver: "embedded-ptr",
+			EmbeddedPtrPtrReceiver:   "embedded-ptr",
+			ShadowedValueReceiver:    "tester",
+			ShadowedPtrReceiver:      "tester",
+		}
+	)
+
+	for _, src := range []interface{}{value, &value} {
+		//
+		// To()
+		//
+
+		dst := &MethodReceiverResource{}
+		assert.Nil(t, deepcopier.Copy(src).To(dst))
+		assert.Equal(t, expected, *dst)
+
+		//
+		// From()
+		//
+
+		dst = &MethodReceiverResource{}
+		assert.Nil(t, deepcopier.Copy(dst).From(src))
+		assert.Equal(t, expected, *dst)
+	}
+
+	//
+	// Nil embedded pointers, shadowed methods being still copied
+	//
+
+	value.MethodReceiverEmbeddedPtr = nil
+	expected.EmbeddedPtrValueReceiver = ""
+	expected.EmbeddedPtrPtrReceiver = ""
+
+	for _, src := range []interface{}{value, &value} {
+		dst := &MethodReceiverResource{}
+		assert.Nil(t, deepcopier.Copy(src).To(dst))
+		assert.Equal(t, expected, *dst)
+
+		dst = &MethodReceiverResource{}
+		assert.Nil(t, deepcopier.Copy(dst).From(src))
+		assert.Equal(t, expected, *dst)
+	}
+}
+
 func TestAnonymousStruct(t *testing.T) {
 	type (
 		Embedded             struct{ Int int }
@@ -824,6 +883,164 @@ func TestNullableType(t *testing.T) {
 	}
 }
 
+func TestSetter(t *testing.T) {
+	type (
+		Src struct {
+			Email    string
+			Username string
+			Age      float64
+			Invalid  string
+		}
+
+		SrcRenamed struct {
+			MyEmail string `deepcopier:"field:Email"`
+			Name    string `deepcopier:"field:Username;setter:ChangeUsername"`
+		}
+	)
+
+	//
+	// To()
+	//
+
+	src := &Src{Email: "gilles@example.com", Username: "gilles", Age: 30}
+	dst := &SetterTester{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, src.Email, dst.Email())
+	assert.Equal(t, "@gilles", dst.Username)
+	assert.Equal(t, 30, dst.Age())
+
+	src = &Src{Email: "invalid"}
+	dst = &SetterTester{}
+	err := deepcopier.Copy(src).To(dst)
+	assert.Equal(t, errInvalidEmail, errors.Unwrap(err))
+	assert.Empty(t, dst.Email())
+
+	type SrcInvalid struct {
+		Name string `deepcopier:"setter:SetInvalid"`
+	}
+
+	srcInvalid := &SrcInvalid{Name: "invalid"}
+	dst = &SetterTester{}
+	assert.NotNil(t, deepcopier.Copy(dst).From(srcInvalid))
+
+	//
+	// From()
+	//
+
+	srcRenamed := &SrcRenamed{MyEmail: "gilles@example.com", Name: "gilles"}
+	dst = &SetterTester{}
+	assert.Nil(t, deepcopier.Copy(dst).From(srcRenamed))
+	assert.Equal(t, srcRenamed.MyEmail, dst.Email())
+	assert.Equal(t, "@gilles", dst.Username)
+}
+
+// ----------------------------------------------------------------------------
+// Setter testers
+// ----------------------------------------------------------------------------
+
+var errInvalidEmail = errors.New("invalid email")
+
+type SetterTester struct {
+	email    string
+	age      int
+	Username string `deepcopier:"setter:ChangeUsername"`
+}
+
+func (s *SetterTester) SetEmail(email string) error {
+	if !strings.Contains(email, "@") {
+		return errInvalidEmail
+	}
+	s.email = email
+	return nil
+}
+
+func (s *SetterTester) Email() string {
+	return s.email
+}
+
+func (s *SetterTester) SetAge(age int) {
+	s.age = age
+}
+
+func (s *SetterTester) Age() int {
+	return s.age
+}
+
+func (s *SetterTester) ChangeUsername(username string) {
+	s.Username = "@" + username
+}
+
+func (s *SetterTester) SetInvalid(a string, b string) {}
+
+// ----------------------------------------------------------------------------
+// Method receiver testers
+// ----------------------------------------------------------------------------
+
+type MethodReceiverEmbedded struct {
+	Name string
+}
+
+func (m MethodReceiverEmbedded) EmbeddedValueReceiver() string {
+	return m.Name
+}
+
+func (m *MethodReceiverEmbedded) EmbeddedPointerReceiver() string {
+	return m.Name
+}
+
+type MethodReceiverEmbeddedPtr struct {
+	Name string
+}
+
+func (m MethodReceiverEmbeddedPtr) EmbeddedPtrValueReceiver() string {
+	return m.Name
+}
+
+func (m *MethodReceiverEmbeddedPtr) EmbeddedPtrPtrReceiver() string {
+	return m.Name
+}
+
+func (m MethodReceiverEmbeddedPtr) ShadowedValueReceiver() string {
+	return m.Name
+}
+
+func (m *MethodReceiverEmbeddedPtr) ShadowedPtrReceiver() string {
+	return m.Name
+}
+
+type MethodReceiverTester struct {
+	MethodReceiverEmbedded
+	*MethodReceiverEmbeddedPtr
+	Name string
+}
+
+func (m MethodReceiverTester) ValueReceiver() string {
+	return m.Name
+}
+
+func (m *MethodReceiverTester) PointerReceiver() string {
+	return m.Name
+}
+
+func (m MethodReceiverTester) ShadowedValueReceiver() string {
+	return m.Name
+}
+
+func (m *MethodReceiverTester) ShadowedPtrReceiver() string {
+	return m.Name
+}
+
+type MethodReceiverResource struct {
+	ValueReceiver            string
+	PointerReceiver          string
+	EmbeddedValueReceiver    string
+	EmbeddedPointerReceiver  string
+	EmbeddedPtrValueReceiver string
+	EmbeddedPtrPtrReceiver   string
+	ShadowedValueReceiver    string
+	ShadowedPtrReceiver      string
+}
+
 // ----------------------------------------------------------------------------
 // Method testers
 // ----------------------------------------------------------------------------
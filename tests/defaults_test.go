@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestDefaults(t *testing.T) {
+	type (
+		Src struct {
+			Name     string
+			Email    sql.NullString
+			Age      int
+			Ratio    *float64
+			Timeout  time.Duration
+			Nullable sql.NullInt64
+		}
+
+		Dst struct {
+			Name      string         `deepcopier:"default:anonymous"`
+			Email     string         `deepcopier:"force; default:'nobody@example.com'"`
+			Age       int            `deepcopier:"default:18"`
+			Ratio     float64        `deepcopier:"default:0.5"`
+			Active    bool           `deepcopier:"default:true"`
+			Timeout   time.Duration  `deepcopier:"default:1m30s"`
+			CreatedAt time.Time      `deepcopier:"default:2020-01-02T15:04:05Z"`
+			Count     *uint          `deepcopier:"default:3"`
+			Nullable  sql.NullString `deepcopier:"default:foo"`
+			NoDefault string
+		}
+	)
+
+	//
+	// To()
+	//
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{Email: sql.NullString{Valid: false}}).To(dst))
+	assert.Equal(t, "anonymous", dst.Name)
+	assert.Equal(t, "nobody@example.com", dst.Email)
+	assert.Equal(t, 18, dst.Age)
+	assert.Equal(t, 0.5, dst.Ratio)
+	assert.True(t, dst.Active)
+	assert.Equal(t, 90*time.Second, dst.Timeout)
+	assert.Equal(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC), dst.CreatedAt)
+	assert.Equal(t, uint(3), *dst.Count)
+	assert.Equal(t, sql.NullString{Valid: true, String: "foo"}, dst.Nullable)
+	assert.Empty(t, dst.NoDefault)
+
+	ratio := 0.8
+	src := &Src{
+		Name:    "gilles",
+		Email:   sql.NullString{Valid: true, String: "gilles@example.com"},
+		Age:     30,
+		Ratio:   &ratio,
+		Timeout: time.Second,
+	}
+
+	dst = &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, "gilles", dst.Name)
+	assert.Equal(t, "gilles@example.com", dst.Email)
+	assert.Equal(t, 30, dst.Age)
+	assert.Equal(t, 0.8, dst.Ratio)
+	assert.Equal(t, time.Second, dst.Timeout)
+
+	//
+	// From()
+	//
+
+	type Model struct {
+		Username string
+	}
+
+	type Resource struct {
+		Name string `deepcopier:"field:Username; default:anonymous"`
+	}
+
+	model := &Model{}
+	assert.Nil(t, deepcopier.Copy(model).From(&Resource{}))
+	assert.Equal(t, "anonymous", model.Username)
+}
+
+func TestDefaults_Func(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name    string `deepcopier:"default:anonymous"`
+			Lang    string
+			Timeout time.Duration
+			Retries int
+		}
+	)
+
+	defaults := func(dst reflect.Type, field reflect.StructField) (interface{}, bool) {
+		switch field.Name {
+		case "Name", "Lang":
+			return "en", true
+		case "Timeout":
+			return "5s", true
+		case "Retries":
+			return 3, true
+		}
+		return nil, false
+	}
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{}).WithDefaults(defaults).To(dst))
+	assert.Equal(t, "anonymous", dst.Name)
+	assert.Equal(t, "en", dst.Lang)
+	assert.Equal(t, 5*time.Second, dst.Timeout)
+	assert.Equal(t, 3, dst.Retries)
+
+	invalid := func(dst reflect.Type, field reflect.StructField) (interface{}, bool) {
+		return []string{}, field.Name == "Retries"
+	}
+
+	err := deepcopier.Copy(&Src{}).WithDefaults(invalid).To(&Dst{})
+
+	var ferr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "Retries", ferr.Path)
+}
+
+func TestDefaults_Invalid(t *testing.T) {
+	type (
+		Src struct {
+			Age int
+		}
+
+		Dst struct {
+			Age int `deepcopier:"default:eighteen"`
+		}
+	)
+
+	// Invalid defaults are reported outside strict mode too
+	for _, dc := range []*deepcopier.DeepCopier{deepcopier.Copy(&Src{}), deepcopier.Copy(&Src{}).Strict()} {
+		dst := &Dst{}
+		err := dc.To(dst)
+
+		var terr *deepcopier.TagError
+		assert.True(t, errors.As(err, &terr))
+		assert.Equal(t, "Age", terr.Field)
+		assert.Zero(t, dst.Age)
+	}
+
+	plan := deepcopier.Explain(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}))
+	assert.Len(t, plan.Errors, 1)
+
+	m, _ := plan.Mapping("Age")
+	assert.Equal(t, "eighteen", m.Default)
+}
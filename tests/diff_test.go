@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestDiff(t *testing.T) {
+	type (
+		Address struct {
+			City    string
+			Country string
+		}
+
+		AddressPayload struct {
+			City    string
+			Country string
+		}
+
+		Payload struct {
+			Name     string `deepcopier:"field:Username"`
+			Email    sql.NullString
+			Age      int
+			Address  AddressPayload
+			Password string
+		}
+
+		Model struct {
+			Username string `deepcopier:"field:Name"`
+			Email    string `deepcopier:"force"`
+			Age      int
+			Address  *Address
+			Password string `deepcopier:"skip"`
+		}
+	)
+
+	var (
+		payload = &Payload{
+			Name:     "gilles",
+			Email:    sql.NullString{Valid: true, String: "gilles@example.com"},
+			Age:      30,
+			Address:  AddressPayload{City: "Paris", Country: "France"},
+			Password: "s3cr3t",
+		}
+		model = &Model{
+			Username: "thoas",
+			Email:    "gilles@example.com",
+			Age:      30,
+			Address:  &Address{City: "Lyon", Country: "France"},
+		}
+	)
+
+	changes, err := deepcopier.Diff(payload, model)
+	assert.Nil(t, err)
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Username", Old: "thoas", New: "gilles"},
+		{Path: "Address.City", Old: "Lyon", New: "Paris"},
+	}, changes)
+
+	// Destination is left untouched
+	assert.Equal(t, "thoas", model.Username)
+	assert.Equal(t, "Lyon", model.Address.City)
+
+	changes, err = deepcopier.Diff(payload, *model)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+}
+
+func TestDiff_Cycle(t *testing.T) {
+	node := &CycleNodeResource{ID: 1}
+	node.Next = node
+
+	changes, err := deepcopier.Diff(&CycleNode{ID: 2}, node)
+	assert.Nil(t, err)
+	assert.Equal(t, []deepcopier.Change{{Path: "ID", Old: 1, New: 2}}, changes)
+	assert.Equal(t, 1, node.ID)
+	assert.True(t, node.Next == node)
+}
+
+func TestChangeLog(t *testing.T) {
+	type (
+		Src struct {
+			Name  string
+			Email string
+			Meta  map[string]interface{}
+		}
+
+		Dst struct {
+			Name  string
+			Email string
+			Meta  struct {
+				Lang string
+			}
+		}
+	)
+
+	var (
+		changes []deepcopier.Change
+		src     = &Src{Name: "gilles", Email: "gilles@example.com", Meta: map[string]interface{}{"Lang": "fr"}}
+	)
+
+	//
+	// To()
+	//
+
+	dst := &Dst{Email: "gilles@example.com"}
+	assert.Nil(t, deepcopier.Copy(src).WithChangeLog(&changes).To(dst))
+	assert.Equal(t, "gilles", dst.Name)
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Name", Old: "", New: "gilles"},
+		{Path: "Meta.Lang", Old: "", New: "fr"},
+	}, changes)
+
+	//
+	// From()
+	//
+
+	changes = nil
+	dst = &Dst{Name: "gilles"}
+	assert.Nil(t, deepcopier.Copy(dst).WithChangeLog(&changes).From(src))
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Email", Old: "", New: "gilles@example.com"},
+		{Path: "Meta.Lang", Old: "", New: "fr"},
+	}, changes)
+
+	//
+	// Map
+	//
+
+	changes = nil
+	values := map[string]interface{}{"Name": "gilles", "Email": "thoas@example.com"}
+	assert.Nil(t, deepcopier.Copy(&Dst{Name: "gilles"}).WithChangeLog(&changes).To(&values))
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Email", Old: "thoas@example.com", New: ""},
+		{Path: "Meta", Old: nil, New: map[string]interface{}{"Lang": ""}},
+	}, changes)
+}
+
+type DiffBag struct {
+	Name  string
+	attrs map[string]string
+	calls int
+}
+
+func (b *DiffBag) SetColor(c string) {
+	b.attrs["color"] = c
+}
+
+func (b *DiffBag) BeforeCopy(src interface{}, ctx deepcopier.Context) error {
+	b.calls++
+	return nil
+}
+
+func (b *DiffBag) Validate() error {
+	return errors.New("invalid bag")
+}
+
+type DiffBagPayload struct {
+	Name  string
+	Color string
+}
+
+func TestDiff_DryRun(t *testing.T) {
+	bag := &DiffBag{Name: "bag", attrs: map[string]string{"color": "red"}}
+
+	changes, err := deepcopier.Diff(&DiffBagPayload{Name: "box", Color: "blue"}, bag)
+	assert.Nil(t, err)
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Name", Old: "bag", New: "box"},
+		{Path: "Color", Old: nil, New: "blue"},
+	}, changes)
+
+	// Setters, hooks and validators are not called
+	assert.Equal(t, map[string]string{"color": "red"}, bag.attrs)
+	assert.Equal(t, 0, bag.calls)
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type DirectionUser struct {
+	Username string
+	Login    string
+	Password string
+	Title    string
+}
+
+func (u *DirectionUser) DisplayName() string {
+	return "@" + u.Username
+}
+
+type DirectionResource struct {
+	DisplayName string `deepcopier:"readonly"`
+	Password    string `deepcopier:"writeonly"`
+	Name        string `deepcopier:"to:Username; from:Login"`
+	Headline    string `deepcopier:"field:Title; readonly"`
+}
+
+func TestDirection(t *testing.T) {
+	user := &DirectionUser{
+		Username: "gilles",
+		Login:    "gilles@example.com",
+		Password: "secret",
+		Title:    "Developer",
+	}
+
+	//
+	// To()
+	//
+
+	resource := &DirectionResource{}
+	assert.Nil(t, deepcopier.Copy(user).To(resource))
+	assert.Equal(t, "@gilles", resource.DisplayName)
+	assert.Empty(t, resource.Password)
+	assert.Equal(t, "gilles", resource.Name)
+	assert.Equal(t, "Developer", resource.Headline)
+
+	//
+	// From()
+	//
+
+	resource = &DirectionResource{
+		DisplayName: "@thoas",
+		Password:    "changed",
+		Name:        "thoas@example.com",
+		Headline:    "Computed",
+	}
+
+	user = &DirectionUser{Username: "thoas", Title: "Developer"}
+	assert.Nil(t, deepcopier.Copy(user).From(resource))
+	assert.Equal(t, "thoas", user.Username)
+	assert.Equal(t, "thoas@example.com", user.Login)
+	assert.Equal(t, "changed", user.Password)
+	assert.Equal(t, "Developer", user.Title)
+}
+
+func TestDirection_Map(t *testing.T) {
+	resource := &DirectionResource{DisplayName: "@gilles", Password: "secret"}
+
+	dst := map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(resource).To(&dst))
+	assert.NotContains(t, dst, "Password")
+	assert.Equal(t, "@gilles", dst["DisplayName"])
+
+	resource = &DirectionResource{}
+	assert.Nil(t, deepcopier.Copy(resource).From(map[string]interface{}{
+		"Password":    "secret",
+		"DisplayName": "@gilles",
+		"Login":       "gilles@example.com",
+	}))
+	assert.Equal(t, "secret", resource.Password)
+	assert.Empty(t, resource.DisplayName)
+	assert.Equal(t, "gilles@example.com", resource.Name)
+}
+
+func TestDirection_Explain(t *testing.T) {
+	var (
+		userType     = reflect.TypeOf(DirectionUser{})
+		resourceType = reflect.TypeOf(DirectionResource{})
+	)
+
+	plan := deepcopier.Explain(userType, resourceType)
+	m, ok := plan.Mapping("Password")
+	assert.True(t, ok)
+	assert.Equal(t, "skip option", m.Skipped)
+
+	plan = deepcopier.Explain(resourceType, userType, deepcopier.Options{Reversed: true})
+	m, ok = plan.Mapping("Login")
+	assert.True(t, ok)
+	assert.Equal(t, "Name", m.Source)
+	m, ok = plan.Mapping("Title")
+	assert.True(t, ok)
+	assert.NotEmpty(t, m.Skipped)
+}
+
+func TestDirection_Strict(t *testing.T) {
+	type Dst struct {
+		Name string `deepcopier:"readonly; writeonly"`
+	}
+
+	err := deepcopier.Copy(&DirectionUser{}).Strict().To(&Dst{})
+	assert.True(t, errors.Is(err, deepcopier.ErrInvalidTag))
+}
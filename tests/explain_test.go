@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestExplain(t *testing.T) {
+	type (
+		Address struct {
+			City string
+		}
+
+		AddressResource struct {
+			City string
+		}
+
+		User struct {
+			Username string
+			Email    sql.NullString
+			Phone    sql.NullString
+			Age      *int
+			Address  Address
+			Password string
+			Internal int
+		}
+
+		UserResource struct {
+			Name     string `deepcopier:"field:Username"`
+			Email    string `deepcopier:"force"`
+			Phone    string
+			Age      int
+			Address  AddressResource
+			Password string `deepcopier:"skip"`
+			Internal string
+			Missing  string
+		}
+	)
+
+	plan := deepcopier.Explain(reflect.TypeOf(&User{}), reflect.TypeOf(UserResource{}))
+	assert.Equal(t, reflect.TypeOf(User{}), plan.Src)
+	assert.Equal(t, reflect.TypeOf(UserResource{}), plan.Dst)
+
+	expected := map[string]struct {
+		source     string
+		conversion deepcopier.Conversion
+		skipped    string
+	}{
+		"Name":     {"Username", deepcopier.ConversionAssign, ""},
+		"Email":    {"Email", deepcopier.ConversionValuer, ""},
+		"Phone":    {"Phone", "", "nullable type requires force option"},
+		"Age":      {"Age", deepcopier.ConversionDereference, ""},
+		"Address":  {"Address", deepcopier.ConversionNested, ""},
+		"Password": {"Password", "", "skip option"},
+		"Internal": {"Internal", "", "incompatible types int and string"},
+		"Missing":  {"", "", "no matching source field or method"},
+	}
+
+	assert.Len(t, plan.Mappings, len(expected))
+
+	for field, e := range expected {
+		m, ok := plan.Mapping(field)
+		assert.True(t, ok, field)
+		assert.Equal(t, e.source, m.Source, field)
+		assert.Equal(t, e.conversion, m.Conversion, field)
+		assert.Equal(t, e.skipped, m.Skipped, field)
+	}
+
+	m, _ := plan.Mapping("Address")
+	nested, ok := m.Nested.Mapping("City")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionAssign, nested.Conversion)
+
+	assert.Empty(t, plan.Unmatched)
+
+	assert.Contains(t, plan.String(), "Address.City")
+	assert.Contains(t, plan.String(), "skipped: skip option")
+}
+
+func TestExplain_Methods(t *testing.T) {
+	plan := deepcopier.Explain(reflect.TypeOf(MethodTesterFoo{}), reflect.TypeOf(MethodTesterBar{}))
+
+	for field, conversion := range map[string]deepcopier.Conversion{
+		"FooInteger":           deepcopier.ConversionAssign,
+		"FooContext":           deepcopier.ConversionAssign,
+		"TagFirst":             deepcopier.ConversionAssign,
+		"FooSliceToSlicePtr":   deepcopier.ConversionReference,
+		"FooStringPtrToString": deepcopier.ConversionDereference,
+	} {
+		m, ok := plan.Mapping(field)
+		assert.True(t, ok, field)
+		assert.True(t, m.Method, field)
+		assert.Equal(t, conversion, m.Conversion, field)
+	}
+
+	m, _ := plan.Mapping("FooSkipped")
+	assert.Equal(t, "skip option", m.Skipped)
+}
+
+func TestExplain_Setters(t *testing.T) {
+	type Src struct {
+		Email   string
+		Unknown string
+	}
+
+	plan := deepcopier.Explain(reflect.TypeOf(Src{}), reflect.TypeOf(SetterTester{}))
+
+	m, ok := plan.Mapping("Email")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionSetter, m.Conversion)
+	assert.Equal(t, "SetEmail", m.Setter)
+
+	assert.Equal(t, []string{"Unknown"}, plan.Unmatched)
+}
+
+func TestExplain_Reversed(t *testing.T) {
+	type (
+		Model struct {
+			Username string
+		}
+
+		Resource struct {
+			Name string `deepcopier:"field:Username"`
+		}
+	)
+
+	plan := deepcopier.Explain(reflect.TypeOf(Resource{}), reflect.TypeOf(Model{}), deepcopier.Options{Reversed: true})
+
+	m, ok := plan.Mapping("Username")
+	assert.True(t, ok)
+	assert.Equal(t, "Name", m.Source)
+	assert.Equal(t, deepcopier.ConversionAssign, m.Conversion)
+}
+
+func TestExplain_Conditions(t *testing.T) {
+	plan := deepcopier.Explain(reflect.TypeOf(ConditionUser{}), reflect.TypeOf(ConditionUserResource{}))
+
+	m, ok := plan.Mapping("Email")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionAssign, m.Conversion)
+	assert.Equal(t, "IsPublic", m.Condition)
+
+	m, ok = plan.Mapping("Contact")
+	assert.True(t, ok)
+	assert.True(t, m.Method)
+	assert.Equal(t, "IsPublic", m.Condition)
+	assert.Contains(t, plan.String(), "(if: is_admin)")
+
+	// Context methods may not take the context
+	plan = deepcopier.Explain(reflect.TypeOf(ContextAuthor{}), reflect.TypeOf(ContextAuthorResource{}))
+
+	for _, field := range []string{"Link", "Followers"} {
+		m, ok = plan.Mapping(field)
+		assert.True(t, ok)
+		assert.Equal(t, deepcopier.ConversionAssign, m.Conversion)
+		assert.Empty(t, m.Skipped)
+	}
+
+	plan = deepcopier.Explain(reflect.TypeOf(ExplainNoContext{}), reflect.TypeOf(ContextAuthorResource{}))
+
+	m, ok = plan.Mapping("Link")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionAssign, m.Conversion)
+	assert.Empty(t, m.Skipped)
+}
+
+type ExplainInvalidCondition struct {
+	Email string
+}
+
+func (ExplainInvalidCondition) IsPublic() string {
+	return ""
+}
+
+type ExplainInvalidConditionResource struct {
+	Email string `deepcopier:"if:IsPublic"`
+}
+
+func TestExplain_InvalidCondition(t *testing.T) {
+	plan := deepcopier.Explain(reflect.TypeOf(ExplainInvalidCondition{}), reflect.TypeOf(ExplainInvalidConditionResource{}))
+
+	// Invalid conditions fail copies
+	m, ok := plan.Mapping("Email")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionAssign, m.Conversion)
+	assert.Empty(t, m.Skipped)
+	assert.Equal(t, "invalid condition method signature func(*tests.ExplainInvalidCondition) string", m.Error)
+	assert.Contains(t, plan.String(), "error: invalid condition method signature")
+
+	err := deepcopier.Copy(&ExplainInvalidCondition{}).To(&ExplainInvalidConditionResource{})
+	assert.EqualError(t, err, "Email: "+m.Error)
+}
+
+type ExplainNoContext struct{}
+
+func (ExplainNoContext) Link() string {
+	return ""
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type Celsius struct {
+	Degrees float64
+}
+
+type ExtensionSrc struct {
+	Temperature Celsius
+	Internal    string
+	Name        string
+}
+
+type ExtensionDst struct {
+	Temperature string
+	Internal    string
+	Name        string
+	Copies      int
+}
+
+// testExtension converts Celsius values to strings, skips Internal fields
+// and counts copies of ExtensionSrc.
+type testExtension struct{}
+
+func (testExtension) SkipField(t reflect.Type, field reflect.StructField) bool {
+	return (t == reflect.TypeOf(ExtensionSrc{}) || t == reflect.TypeOf(ExtensionDst{})) && field.Name == "Internal"
+}
+
+func (testExtension) Convert(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if c, ok := value.Interface().(Celsius); ok && t.Kind() == reflect.String {
+		return reflect.ValueOf(fmt.Sprintf("%.1f°C", c.Degrees)), true
+	}
+	return reflect.Value{}, false
+}
+
+func (testExtension) AfterCopy(dst reflect.Value, src reflect.Value, options deepcopier.Options) error {
+	if d, ok := dst.Addr().Interface().(*ExtensionDst); ok && src.Type() == reflect.TypeOf(ExtensionSrc{}) {
+		d.Copies++
+	}
+	return nil
+}
+
+func init() {
+	deepcopier.Use(testExtension{})
+}
+
+func TestExtension(t *testing.T) {
+	src := &ExtensionSrc{
+		Temperature: Celsius{Degrees: 21.5},
+		Internal:    "secret",
+		Name:        "gilles",
+	}
+
+	dst := &ExtensionDst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, "21.5°C", dst.Temperature)
+	assert.Empty(t, dst.Internal)
+	assert.Equal(t, "gilles", dst.Name)
+	assert.Equal(t, 1, dst.Copies)
+
+	m := map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(src).To(&m))
+	assert.NotContains(t, m, "Internal")
+
+	plan := deepcopier.Explain(reflect.TypeOf(ExtensionSrc{}), reflect.TypeOf(ExtensionDst{}))
+	mapping, ok := plan.Mapping("Temperature")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionConvert, mapping.Conversion)
+	assert.NotContains(t, plan.Unmatched, "Internal")
+}
@@ -1,6 +1,6 @@
 module github.com/ulule/deepcopier/tests
 
-go 1.14
+go 1.23
 
 require (
 	github.com/guregu/null v4.0.0+incompatible
@@ -10,4 +10,10 @@ require (
 	github.com/ulule/deepcopier v0.0.0
 )
 
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
 replace github.com/ulule/deepcopier => ../
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestHooks(t *testing.T) {
+	var (
+		c   = map[string]interface{}{"secret": "[redacted]"}
+		src = &HookModel{FirstName: "gilles", LastName: "fabio", Password: "s3cr3t"}
+	)
+
+	//
+	// To()
+	//
+
+	dst := &HookResource{}
+	assert.Nil(t, deepcopier.Copy(src).WithContext(c).To(dst))
+	assert.Equal(t, "gilles fabio", dst.Name)
+	assert.Equal(t, "[redacted]", dst.Password)
+	assert.Equal(t, []string{"model:before:*tests.HookResource", "resource:before", "resource:after", "model:after:*tests.HookResource"}, dst.Calls)
+	assert.Equal(t, "s3cr3t", src.Password)
+
+	//
+	// From()
+	//
+
+	dst = &HookResource{}
+	assert.Nil(t, deepcopier.Copy(dst).WithContext(c).From(*src))
+	assert.Equal(t, "gilles fabio", dst.Name)
+	assert.Equal(t, "[redacted]", dst.Password)
+}
+
+func TestHooks_Nested(t *testing.T) {
+	type (
+		Src struct {
+			Rel HookModel
+		}
+
+		Dst struct {
+			Rel HookResource
+		}
+	)
+
+	src := &Src{Rel: HookModel{FirstName: "gilles", LastName: "fabio"}}
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, "gilles fabio", dst.Rel.Name)
+	assert.Len(t, dst.Rel.Calls, 4)
+}
+
+func TestHooks_Error(t *testing.T) {
+	src := &HookModel{FirstName: "gilles", Password: "s3cr3t"}
+
+	dst := &HookResource{}
+	assert.Equal(t, errMissingLastName, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, []string{"model:before:*tests.HookResource", "resource:before"}, dst.Calls)
+	assert.Empty(t, dst.Name)
+
+	src = &HookModel{FirstName: "gilles", LastName: "fabio", Password: "invalid"}
+	dst = &HookResource{}
+	assert.Equal(t, errInvalidPassword, deepcopier.Copy(src).To(dst))
+	assert.Empty(t, dst.Calls)
+	assert.Empty(t, dst.Password)
+}
+
+// ----------------------------------------------------------------------------
+// Hook testers
+// ----------------------------------------------------------------------------
+
+var (
+	errMissingLastName = errors.New("missing last name")
+	errInvalidPassword = errors.New("invalid password")
+)
+
+type HookModel struct {
+	FirstName string
+	LastName  string
+	Password  string
+}
+
+func (m *HookModel) BeforeCopyTo(dst interface{}, ctx deepcopier.Context) error {
+	if m.Password == "invalid" {
+		return errInvalidPassword
+	}
+
+	if r, ok := dst.(*HookResource); ok {
+		r.Calls = append(r.Calls, fmt.Sprintf("model:before:%T", dst))
+	}
+
+	return nil
+}
+
+func (m HookModel) AfterCopyTo(dst interface{}, ctx deepcopier.Context) error {
+	if r, ok := dst.(*HookResource); ok {
+		r.Calls = append(r.Calls, fmt.Sprintf("model:after:%T", dst))
+	}
+	return nil
+}
+
+type HookResource struct {
+	FirstName string
+	LastName  string
+	Password  string
+	Name      string   `deepcopier:"skip"`
+	Calls     []string `deepcopier:"skip"`
+}
+
+func (r *HookResource) BeforeCopy(src interface{}, ctx deepcopier.Context) error {
+	r.Calls = append(r.Calls, "resource:before")
+	return nil
+}
+
+func (r *HookResource) AfterCopy(src interface{}, ctx deepcopier.Context) error {
+	if r.LastName == "" {
+		return errMissingLastName
+	}
+
+	r.Calls = append(r.Calls, "resource:after")
+	r.Name = r.FirstName + " " + r.LastName
+
+	if secret, ok := ctx.Values["secret"].(string); ok {
+		r.Password = secret
+	}
+
+	return nil
+}
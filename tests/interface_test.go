@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type InterfaceItem interface {
+	ItemID() int
+}
+
+type InterfacePost struct {
+	ID    int
+	Title string
+}
+
+func (p *InterfacePost) ItemID() int { return p.ID }
+
+type InterfacePhoto struct {
+	ID  int
+	URL string
+}
+
+func (p InterfacePhoto) ItemID() int { return p.ID }
+
+type InterfaceVideo struct {
+	ID int
+}
+
+func (v *InterfaceVideo) ItemID() int { return v.ID }
+
+type InterfaceItemResource interface {
+	Kind() string
+}
+
+type InterfacePostResource struct {
+	Type  string
+	ID    int
+	Title string
+}
+
+func (r *InterfacePostResource) Kind() string { return "post" }
+
+type InterfacePhotoResource struct {
+	Type string
+	ID   int
+	Link string `deepcopier:"field:URL"`
+}
+
+func (r InterfacePhotoResource) Kind() string { return "photo" }
+
+type InterfaceFeed struct {
+	Main    InterfaceItem
+	Cover   InterfaceItem
+	Photo   InterfacePhoto
+	Other   interface{}
+	Unknown InterfaceItem
+	Nil     InterfaceItem
+}
+
+func init() {
+	deepcopier.RegisterTarget[InterfacePost, InterfacePostResource](deepcopier.TargetOptions{
+		Discriminator: "Type",
+		Value:         "post",
+	})
+	deepcopier.RegisterTarget[*InterfacePhoto, InterfacePhotoResource](deepcopier.TargetOptions{
+		Discriminator: "Type",
+		Value:         "photo",
+	})
+}
+
+func TestInterface_Source(t *testing.T) {
+	type FeedResource struct {
+		Main  InterfacePostResource
+		Cover *InterfacePhotoResource
+		Other InterfacePost
+		Nil   InterfacePostResource
+	}
+
+	feed := &InterfaceFeed{
+		Main:  &InterfacePost{ID: 1, Title: "foo"},
+		Cover: InterfacePhoto{ID: 2, URL: "http://example.com/bar.jpg"},
+		Other: &InterfacePost{ID: 3, Title: "baz"},
+	}
+
+	resource := &FeedResource{}
+	assert.Nil(t, deepcopier.Copy(feed).To(resource))
+	assert.Equal(t, InterfacePostResource{ID: 1, Title: "foo"}, resource.Main)
+	assert.Equal(t, &InterfacePhotoResource{ID: 2, Link: "http://example.com/bar.jpg"}, resource.Cover)
+	assert.Equal(t, InterfacePost{ID: 3, Title: "baz"}, resource.Other)
+	assert.Equal(t, InterfacePostResource{}, resource.Nil)
+
+	plan := deepcopier.Explain(reflect.TypeOf(InterfaceFeed{}), reflect.TypeOf(FeedResource{}))
+	m, ok := plan.Mapping("Main")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionDynamic, m.Conversion)
+}
+
+func TestInterface_Destination(t *testing.T) {
+	type FeedResource struct {
+		Main    InterfaceItemResource
+		Cover   InterfaceItemResource
+		Photo   InterfaceItemResource
+		Other   InterfaceItemResource
+		Unknown InterfaceItemResource
+		Nil     InterfaceItemResource
+	}
+
+	feed := &InterfaceFeed{
+		Main:  &InterfacePost{ID: 1, Title: "foo"},
+		Cover: InterfacePhoto{ID: 2, URL: "http://example.com/bar.jpg"},
+		Photo: InterfacePhoto{ID: 3, URL: "http://example.com/baz.jpg"},
+		Other: InterfacePost{ID: 4},
+	}
+
+	resource := &FeedResource{}
+	assert.Nil(t, deepcopier.Copy(feed).To(resource))
+	assert.Equal(t, &InterfacePostResource{Type: "post", ID: 1, Title: "foo"}, resource.Main)
+	assert.Equal(t, &InterfacePhotoResource{Type: "photo", ID: 2, Link: "http://example.com/bar.jpg"}, resource.Cover)
+	assert.Equal(t, &InterfacePhotoResource{Type: "photo", ID: 3, Link: "http://example.com/baz.jpg"}, resource.Photo)
+	assert.Equal(t, &InterfacePostResource{Type: "post", ID: 4}, resource.Other)
+	assert.Nil(t, resource.Unknown)
+	assert.Nil(t, resource.Nil)
+
+	plan := deepcopier.Explain(reflect.TypeOf(InterfaceFeed{}), reflect.TypeOf(FeedResource{}))
+	m, ok := plan.Mapping("Photo")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionDynamic, m.Conversion)
+
+	assert.Panics(t, func() {
+		deepcopier.RegisterTarget[InterfacePost, InterfaceItemResource]()
+	})
+}
+
+func TestInterface_Polymorphic(t *testing.T) {
+	type Timeline struct {
+		Items  []InterfaceItem
+		ByName map[string]InterfaceItem
+		Posts  []*InterfacePost
+	}
+
+	type TimelineResource struct {
+		Items  []InterfaceItemResource
+		ByName map[string]InterfaceItemResource
+		Posts  []InterfaceItemResource
+	}
+
+	timeline := &Timeline{
+		Items: []InterfaceItem{
+			&InterfacePost{ID: 1, Title: "foo"},
+			InterfacePhoto{ID: 2, URL: "http://example.com/bar.jpg"},
+			nil,
+		},
+		ByName: map[string]InterfaceItem{"foo": &InterfacePost{ID: 1, Title: "foo"}},
+		Posts:  []*InterfacePost{{ID: 3}},
+	}
+
+	resource := &TimelineResource{}
+	assert.Nil(t, deepcopier.Copy(timeline).To(resource))
+	assert.Equal(t, []InterfaceItemResource{
+		&InterfacePostResource{Type: "post", ID: 1, Title: "foo"},
+		&InterfacePhotoResource{Type: "photo", ID: 2, Link: "http://example.com/bar.jpg"},
+		nil,
+	}, resource.Items)
+	assert.Equal(t, map[string]InterfaceItemResource{"foo": &InterfacePostResource{Type: "post", ID: 1, Title: "foo"}}, resource.ByName)
+	assert.Equal(t, []InterfaceItemResource{&InterfacePostResource{Type: "post", ID: 3}}, resource.Posts)
+
+	//
+	// Top-level and concrete elements
+	//
+
+	var resources []InterfaceItemResource
+	assert.Nil(t, deepcopier.Copy(timeline.Items).To(&resources))
+	assert.Len(t, resources, 3)
+
+	var posts []InterfacePostResource
+	assert.Nil(t, deepcopier.Copy([]InterfaceItem{&InterfacePost{ID: 4}, nil}).To(&posts))
+	assert.Equal(t, []InterfacePostResource{{ID: 4}, {}}, posts)
+
+	//
+	// Unregistered types
+	//
+
+	timeline.Items = append(timeline.Items, &InterfaceVideo{ID: 5})
+
+	err := deepcopier.Copy(timeline).To(&TimelineResource{})
+	assert.True(t, errors.Is(err, deepcopier.ErrUnregisteredType))
+	assert.Equal(t, "Items.3: unregistered type *tests.InterfaceVideo", err.Error())
+
+	type FeedResource struct {
+		Unknown InterfaceItemResource
+	}
+
+	err = deepcopier.Copy(&InterfaceFeed{Unknown: &InterfaceVideo{ID: 5}}).To(&FeedResource{})
+	assert.True(t, errors.Is(err, deepcopier.ErrUnregisteredType))
+
+	assert.Panics(t, func() {
+		deepcopier.RegisterTarget[InterfacePost, InterfacePostResource](deepcopier.TargetOptions{Discriminator: "Kind"})
+	})
+}
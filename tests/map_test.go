@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestMap_StructToMap(t *testing.T) {
+	type (
+		Rel struct {
+			Int int
+		}
+
+		Src struct {
+			Int       int
+			Renamed   string `deepcopier:"field:name"`
+			Skipped   string `deepcopier:"skip"`
+			Nullable  sql.NullString
+			Struct    Rel
+			StructPtr *Rel
+			NilPtr    *Rel
+		}
+	)
+
+	src := &Src{
+		Int:       1,
+		Renamed:   "gilles",
+		Skipped:   "I should be skipped",
+		Nullable:  sql.NullString{Valid: true, String: "foo"},
+		Struct:    Rel{Int: 2},
+		StructPtr: &Rel{Int: 3},
+	}
+
+	//
+	// To()
+	//
+
+	dst := map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(src).To(&dst))
+	assert.Equal(t, map[string]interface{}{
+		"Int":       1,
+		"name":      "gilles",
+		"Nullable":  src.Nullable,
+		"Struct":    map[string]interface{}{"Int": 2},
+		"StructPtr": map[string]interface{}{"Int": 3},
+		"NilPtr":    nil,
+	}, dst)
+
+	var nilMap map[string]interface{}
+	assert.Nil(t, deepcopier.Copy(src).To(&nilMap))
+	assert.Equal(t, dst, nilMap)
+
+	strings := map[string]string{}
+	assert.Nil(t, deepcopier.Copy(src).To(&strings))
+	assert.Equal(t, map[string]string{"name": "gilles", "Nullable": "foo"}, strings)
+
+	//
+	// From()
+	//
+
+	dst = map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(&dst).From(src))
+	assert.Equal(t, "gilles", dst["name"])
+	assert.NotContains(t, dst, "Skipped")
+}
+
+func TestMap_MapToStruct(t *testing.T) {
+	type (
+		Rel struct {
+			Int int
+		}
+
+		Dst struct {
+			Int       int
+			Uint      uint8
+			Float     float32
+			Renamed   string `deepcopier:"field:name"`
+			Skipped   string `deepcopier:"skip"`
+			Nullable  sql.NullString
+			StringPtr *string
+			Struct    Rel
+			StructPtr *Rel
+			Missing   string
+		}
+	)
+
+	src := map[string]interface{}{
+		"Int":       float64(1),
+		"Uint":      2,
+		"Float":     1.5,
+		"name":      "gilles",
+		"Skipped":   "I should be skipped",
+		"Nullable":  "foo",
+		"StringPtr": "bar",
+		"Struct":    map[string]interface{}{"Int": float64(2)},
+		"StructPtr": map[string]interface{}{"Int": float64(3)},
+	}
+
+	//
+	// To()
+	//
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, 1, dst.Int)
+	assert.Equal(t, uint8(2), dst.Uint)
+	assert.Equal(t, float32(1.5), dst.Float)
+	assert.Equal(t, "gilles", dst.Renamed)
+	assert.Zero(t, dst.Skipped)
+	assert.Equal(t, sql.NullString{Valid: true, String: "foo"}, dst.Nullable)
+	assert.Equal(t, "bar", *dst.StringPtr)
+	assert.Equal(t, Rel{Int: 2}, dst.Struct)
+	assert.Equal(t, &Rel{Int: 3}, dst.StructPtr)
+	assert.Zero(t, dst.Missing)
+
+	//
+	// From()
+	//
+
+	dst = &Dst{}
+	assert.Nil(t, deepcopier.Copy(dst).From(&src))
+	assert.Equal(t, 1, dst.Int)
+	assert.Equal(t, "gilles", dst.Renamed)
+	assert.Equal(t, &Rel{Int: 3}, dst.StructPtr)
+}
+
+func TestMap_JSON(t *testing.T) {
+	type (
+		Tag struct {
+			Name string
+		}
+
+		Dst struct {
+			Tags    []Tag
+			TagPtrs []*Tag
+			Ids     []int
+			Names   []string
+			Matrix  [][]float32
+			Invalid []int
+		}
+	)
+
+	var src map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{
+		"Tags": [{"Name": "a"}, null],
+		"TagPtrs": [{"Name": "b"}],
+		"Ids": [1, 2],
+		"Names": ["foo", "bar"],
+		"Matrix": [[1.5], [2]],
+		"Invalid": [1, "two"]
+	}`), &src))
+
+	dst := &Dst{Invalid: []int{3}}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, []Tag{{Name: "a"}, {}}, dst.Tags)
+	assert.Equal(t, []*Tag{{Name: "b"}}, dst.TagPtrs)
+	assert.Equal(t, []int{1, 2}, dst.Ids)
+	assert.Equal(t, []string{"foo", "bar"}, dst.Names)
+	assert.Equal(t, [][]float32{{1.5}, {2}}, dst.Matrix)
+	assert.Equal(t, []int{3}, dst.Invalid)
+}
+
+func TestMap_Unconvertible(t *testing.T) {
+	type Dst struct {
+		Int  int
+		Uint uint
+	}
+
+	src := map[string]interface{}{
+		"Int":  1.5,
+		"Uint": -1,
+	}
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Zero(t, dst.Int)
+	assert.Zero(t, dst.Uint)
+}
+
+func TestMap_NestedField(t *testing.T) {
+	type (
+		Rel struct {
+			Int int
+		}
+
+		RelResource struct {
+			MyInt int `deepcopier:"field:Int"`
+		}
+
+		Src struct {
+			Rel  Rel
+			Meta map[string]interface{}
+		}
+
+		Dst struct {
+			Rel  *RelResource
+			Meta RelResource
+		}
+	)
+
+	src := &Src{
+		Rel:  Rel{Int: 1},
+		Meta: map[string]interface{}{"Int": 2},
+	}
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, &RelResource{MyInt: 1}, dst.Rel)
+	assert.Equal(t, RelResource{MyInt: 2}, dst.Meta)
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+type MergeLineItem struct {
+	ID       int
+	Product  string
+	Quantity int
+}
+
+type MergeOrder struct {
+	LineItems []*MergeLineItem
+	Notes     []MergeLineItem
+}
+
+type MergeLineItemPayload struct {
+	ID       int
+	Quantity int
+}
+
+type MergeOrderPayload struct {
+	LineItems []MergeLineItemPayload `deepcopier:"mergekey:ID; orphan:delete"`
+	Notes     []MergeLineItemPayload `deepcopier:"mergekey:ID"`
+}
+
+var errNegativeQuantity = errors.New("negative quantity")
+
+type MergeValidatedLine struct {
+	ID       int
+	Quantity int
+}
+
+func (l *MergeValidatedLine) Validate() error {
+	if l.Quantity < 0 {
+		return errNegativeQuantity
+	}
+	return nil
+}
+
+type MergeValidatedOrder struct {
+	Lines []MergeValidatedLine
+}
+
+type MergeValidatedPayload struct {
+	Lines []MergeLineItemPayload `deepcopier:"mergekey:ID"`
+}
+
+func TestMerge(t *testing.T) {
+	var (
+		first = &MergeLineItem{ID: 1, Product: "book", Quantity: 1}
+		order = &MergeOrder{
+			LineItems: []*MergeLineItem{first, {ID: 2, Product: "pen", Quantity: 3}},
+			Notes:     []MergeLineItem{{ID: 1, Product: "gift", Quantity: 1}, {ID: 2, Product: "card", Quantity: 1}},
+		}
+		payload = &MergeOrderPayload{
+			LineItems: []MergeLineItemPayload{{ID: 1, Quantity: 2}, {ID: 3, Quantity: 1}},
+			Notes:     []MergeLineItemPayload{{ID: 2, Quantity: 5}},
+		}
+	)
+
+	assert.Nil(t, deepcopier.Copy(order).From(payload))
+
+	// Matching items are updated in place, new ones appended and orphans removed
+	assert.Equal(t, []*MergeLineItem{
+		{ID: 1, Product: "book", Quantity: 2},
+		{ID: 3, Quantity: 1},
+	}, order.LineItems)
+	assert.True(t, first == order.LineItems[0])
+
+	// Orphans are kept by default
+	assert.Equal(t, []MergeLineItem{
+		{ID: 1, Product: "gift", Quantity: 1},
+		{ID: 2, Product: "card", Quantity: 5},
+	}, order.Notes)
+
+	//
+	// Nil and empty sources
+	//
+
+	order = &MergeOrder{LineItems: []*MergeLineItem{first}}
+	assert.Nil(t, deepcopier.Copy(order).From(&MergeOrderPayload{}))
+	assert.Equal(t, []*MergeLineItem{first}, order.LineItems)
+
+	assert.Nil(t, deepcopier.Copy(order).From(&MergeOrderPayload{LineItems: []MergeLineItemPayload{}}))
+	assert.Empty(t, order.LineItems)
+
+	order = &MergeOrder{}
+	assert.Nil(t, deepcopier.Copy(order).From(&MergeOrderPayload{Notes: []MergeLineItemPayload{}}))
+	assert.Nil(t, order.Notes)
+
+	//
+	// Zero keys never match
+	//
+
+	order = &MergeOrder{Notes: []MergeLineItem{{Product: "gift"}}}
+	assert.Nil(t, deepcopier.Copy(order).From(&MergeOrderPayload{Notes: []MergeLineItemPayload{{Quantity: 1}}}))
+	assert.Equal(t, []MergeLineItem{{Product: "gift"}, {Quantity: 1}}, order.Notes)
+}
+
+func TestMerge_Changes(t *testing.T) {
+	var (
+		changes []deepcopier.Change
+		order   = &MergeOrder{Notes: []MergeLineItem{{ID: 1, Product: "gift", Quantity: 1}}}
+	)
+
+	payload := &MergeOrderPayload{Notes: []MergeLineItemPayload{{ID: 1, Quantity: 2}}}
+	assert.Nil(t, deepcopier.Copy(order).WithChangeLog(&changes).From(payload))
+	assert.Equal(t, []deepcopier.Change{
+		{Path: "Notes.0.Quantity", Old: 1, New: 2},
+	}, changes)
+
+	// Appended and removed elements are recorded as changes of the slice
+	changes = nil
+	payload = &MergeOrderPayload{Notes: []MergeLineItemPayload{{ID: 1, Quantity: 2}, {ID: 2, Quantity: 1}}}
+	assert.Nil(t, deepcopier.Copy(order).WithChangeLog(&changes).From(payload))
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "Notes", changes[0].Path)
+}
+
+func TestMerge_Errors(t *testing.T) {
+	type (
+		Payload struct {
+			Items []MergeLineItemPayload `deepcopier:"mergekey:Product"`
+		}
+
+		Order struct {
+			Items []MergeLineItem
+		}
+
+		InvalidPayload struct {
+			Items []MergeLineItemPayload `deepcopier:"mergekey:ID; orphan:drop"`
+		}
+	)
+
+	// Keys must be copied from the source
+	err := deepcopier.Copy(&Order{}).From(&Payload{Items: []MergeLineItemPayload{{ID: 1}}})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Items: merge key Product is not copied from tests.MergeLineItemPayload", err.Error())
+
+	// Errors of elements copied in place have the index of their changes
+	var (
+		changes []deepcopier.Change
+		lines   = &MergeValidatedOrder{Lines: []MergeValidatedLine{{ID: 1}, {ID: 2}}}
+	)
+
+	err = deepcopier.Copy(lines).WithChangeLog(&changes).From(&MergeValidatedPayload{Lines: []MergeLineItemPayload{{ID: 2, Quantity: -1}}})
+	assert.True(t, errors.Is(err, errNegativeQuantity))
+	assert.True(t, strings.HasPrefix(err.Error(), "Lines.1: "))
+	assert.Equal(t, "Lines.1.Quantity", changes[0].Path)
+
+	// Orphan option must be valid in strict mode
+	err = deepcopier.Copy(&Order{}).Strict().From(&InvalidPayload{})
+	assert.True(t, errors.Is(err, deepcopier.ErrInvalidTag))
+}
+
+func TestMerge_Explain(t *testing.T) {
+	plan := deepcopier.Explain(reflect.TypeOf(MergeOrderPayload{}), reflect.TypeOf(MergeOrder{}), deepcopier.Options{Reversed: true})
+
+	m, ok := plan.Mapping("LineItems")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionMerge, m.Conversion)
+	assert.NotNil(t, m.Nested)
+}
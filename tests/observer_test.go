@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+	"github.com/ulule/deepcopier/metrics"
+)
+
+// recordingObserver records observed copies and fields.
+type recordingObserver struct {
+	mu      sync.Mutex
+	starts  int
+	ends    int
+	errs    []error
+	copied  []string
+	skipped []string
+}
+
+func (o *recordingObserver) OnCopyStart(dst interface{}, src interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.starts++
+}
+
+func (o *recordingObserver) OnField(path string, copied bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if copied {
+		o.copied = append(o.copied, path)
+	} else {
+		o.skipped = append(o.skipped, path)
+	}
+}
+
+func (o *recordingObserver) OnCopyEnd(dst interface{}, src interface{}, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.ends++
+	o.errs = append(o.errs, err)
+}
+
+func TestObserver(t *testing.T) {
+	type Author struct {
+		Name string
+	}
+
+	type Post struct {
+		Title  string
+		Author *Author
+		Rating *int
+	}
+
+	type AuthorResource struct {
+		Name string
+	}
+
+	type PostResource struct {
+		Title  string
+		Author AuthorResource
+		Rating int
+	}
+
+	observer := &recordingObserver{}
+
+	post := &Post{Title: "foo", Author: &Author{Name: "gilles"}}
+	assert.Nil(t, deepcopier.Copy(post).WithObserver(observer).To(&PostResource{}))
+	assert.Equal(t, 1, observer.starts)
+	assert.Equal(t, 1, observer.ends)
+	assert.Equal(t, []error{nil}, observer.errs)
+	assert.Equal(t, []string{"Title", "Author.Name", "Author"}, observer.copied)
+	assert.Equal(t, []string{"Rating"}, observer.skipped)
+
+	//
+	// Errors and streams
+	//
+
+	observer = &recordingObserver{}
+
+	err := deepcopier.Copy(&CollectionRow{ID: -1}).WithObserver(observer).To(&CollectionRowResource{})
+	assert.True(t, errors.Is(err, errInvalidRow))
+	assert.Equal(t, []error{err}, observer.errs)
+
+	for range deepcopier.MapSeq[CollectionRow, *CollectionRowResource](func(yield func(CollectionRow) bool) {
+		yield(CollectionRow{ID: 1})
+	}, deepcopier.Options{Observer: observer}) {
+	}
+
+	assert.Equal(t, 2, observer.starts)
+	assert.Equal(t, 2, observer.ends)
+
+	//
+	// Global observer
+	//
+
+	observer = &recordingObserver{}
+
+	deepcopier.SetObserver(observer)
+	defer deepcopier.SetObserver(nil)
+
+	assert.Nil(t, deepcopier.Copy(post).To(&PostResource{}))
+	assert.Equal(t, 1, observer.ends)
+
+	other := &recordingObserver{}
+	assert.Nil(t, deepcopier.Copy(post).WithObserver(other).To(&PostResource{}))
+	assert.Equal(t, 1, observer.ends)
+	assert.Equal(t, 1, other.ends)
+}
+
+type ObserverSetterResource struct {
+	name string
+	tags []string
+}
+
+func (r *ObserverSetterResource) SetName(name string) {
+	r.name = name
+}
+
+func (r *ObserverSetterResource) SetTags(tags []string) {
+	r.tags = tags
+}
+
+func TestObserver_Setters(t *testing.T) {
+	type Src struct {
+		Name string
+		Tags int
+	}
+
+	observer := &recordingObserver{}
+
+	// Setters taking unconvertible values are not called
+	resource := &ObserverSetterResource{}
+	assert.Nil(t, deepcopier.Copy(&Src{Name: "foo", Tags: 1}).WithObserver(observer).To(resource))
+	assert.Equal(t, "foo", resource.name)
+	assert.Nil(t, resource.tags)
+	assert.Equal(t, []string{"Name"}, observer.copied)
+	assert.Equal(t, []string{"Tags"}, observer.skipped)
+}
+
+func TestObserver_Maps(t *testing.T) {
+	type (
+		Author struct {
+			Name string
+		}
+
+		Post struct {
+			Title  string
+			Author *Author
+			Rating int
+		}
+	)
+
+	observer := &recordingObserver{}
+
+	m := map[string]interface{}{}
+	assert.Nil(t, deepcopier.Copy(&Post{Title: "foo", Author: &Author{Name: "gilles"}}).WithObserver(observer).To(&m))
+	assert.Equal(t, []string{"Title", "Author.Name", "Author", "Rating"}, observer.copied)
+
+	observer = &recordingObserver{}
+
+	post := &Post{}
+	assert.Nil(t, deepcopier.Copy(map[string]interface{}{
+		"Title":  "foo",
+		"Author": map[string]interface{}{"Name": "gilles"},
+		"Rating": "five",
+	}).WithObserver(observer).To(post))
+	assert.Equal(t, []string{"Title", "Author.Name", "Author"}, observer.copied)
+	assert.Equal(t, []string{"Rating"}, observer.skipped)
+}
+
+func TestMetrics(t *testing.T) {
+	observer := metrics.New("deepcopier_test")
+	assert.Equal(t, observer.Vars(), expvar.Get("deepcopier_test"))
+
+	assert.Nil(t, deepcopier.Copy(flatUser()).WithObserver(observer).To(&FlatUserResource{}))
+	assert.NotNil(t, deepcopier.Copy(&CollectionRow{ID: -1}).WithObserver(observer).To(&CollectionRowResource{}))
+
+	vars := observer.Vars()
+	assert.Equal(t, "2", vars.Get("copies").String())
+	assert.Equal(t, "1", vars.Get("errors").String())
+	assert.Equal(t, "0", vars.Get("in_flight").String())
+	assert.Equal(t, "8", vars.Get("fields_copied").String())
+	assert.Equal(t, "0", vars.Get("fields_skipped").String())
+	assert.NotEqual(t, "0", vars.Get("duration_ns").String())
+	assert.Equal(t, "1", vars.Get("copies_by_type").(*expvar.Map).Get("*tests.FlatUser -> *tests.FlatUserResource").String())
+
+	var latencies int64
+	vars.Get("latency").(*expvar.Map).Do(func(kv expvar.KeyValue) {
+		latencies += kv.Value.(*expvar.Int).Value()
+	})
+	assert.Equal(t, int64(2), latencies)
+
+	assert.Panics(t, func() {
+		metrics.New("deepcopier_test")
+	})
+}
+
+func TestMetrics_Latency(t *testing.T) {
+	observer := metrics.New("deepcopier_latency_test")
+
+	for _, d := range []time.Duration{
+		0,
+		time.Microsecond,
+		time.Microsecond + 1,
+		10 * time.Microsecond,
+		time.Second,
+		time.Second + 1,
+		time.Minute,
+	} {
+		observer.OnCopyStart(nil, nil)
+		observer.OnCopyEnd(nil, nil, d, nil)
+	}
+
+	latency := observer.Vars().Get("latency").(*expvar.Map)
+	assert.Equal(t, "2", latency.Get("le_1µs").String())
+	assert.Equal(t, "2", latency.Get("le_10µs").String())
+	assert.Equal(t, "0", latency.Get("le_100µs").String())
+	assert.Equal(t, "1", latency.Get("le_1s").String())
+	assert.Equal(t, "2", latency.Get("inf").String())
+	assert.Equal(t, "0", observer.Vars().Get("in_flight").String())
+	assert.Equal(t, "7", observer.Vars().Get("copies").String())
+}
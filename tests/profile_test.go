@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+// Types below stand for third-party types which cannot be tagged.
+
+type ProfileAddress struct {
+	ZipCode string
+}
+
+type ProfileUser struct {
+	ID        int
+	FirstName string
+	LastName  string
+	Email     string
+	Password  string
+	Role      string
+	Address   ProfileAddress
+}
+
+func (u ProfileUser) Initials() string {
+	return u.FirstName[:1] + u.LastName[:1]
+}
+
+type ProfileAddressResource struct {
+	PostalCode string
+}
+
+type ProfileUserResource struct {
+	ID       string
+	Name     string
+	Mail     string
+	Password string
+	Role     string `deepcopier:"skip"`
+	Short    string
+	Address  ProfileAddressResource
+}
+
+func init() {
+	deepcopier.Register(deepcopier.Profile[ProfileUser, ProfileUserResource]{
+		Fields: map[string]string{
+			"Mail":  "Email",
+			"Role":  "Role",
+			"Short": "Initials",
+		},
+		Skip: []string{"Password"},
+		Converters: map[string]func(interface{}) (interface{}, error){
+			"ID": func(v interface{}) (interface{}, error) {
+				return fmt.Sprintf("user-%d", v), nil
+			},
+			"Short": func(v interface{}) (interface{}, error) {
+				return strings.ToLower(v.(string)), nil
+			},
+		},
+		Resolvers: map[string]func(ProfileUser) (interface{}, error){
+			"Name": func(u ProfileUser) (interface{}, error) {
+				return u.FirstName + " " + u.LastName, nil
+			},
+		},
+	})
+
+	deepcopier.Register(deepcopier.Profile[ProfileAddress, ProfileAddressResource]{
+		Fields: map[string]string{"PostalCode": "ZipCode"},
+	})
+
+	deepcopier.Register(deepcopier.Profile[ProfileUserResource, ProfileUser]{
+		Fields: map[string]string{"Email": "Mail"},
+		Skip:   []string{"ID"},
+	})
+}
+
+func TestProfile(t *testing.T) {
+	user := &ProfileUser{
+		ID:        1,
+		FirstName: "Gilles",
+		LastName:  "Fabio",
+		Email:     "gilles@example.com",
+		Password:  "secret",
+		Role:      "admin",
+		Address:   ProfileAddress{ZipCode: "75011"},
+	}
+
+	//
+	// To()
+	//
+
+	resource := &ProfileUserResource{}
+	assert.Nil(t, deepcopier.Copy(user).To(resource))
+	assert.Equal(t, "user-1", resource.ID)
+	assert.Equal(t, "Gilles Fabio", resource.Name)
+	assert.Equal(t, "gilles@example.com", resource.Mail)
+	assert.Empty(t, resource.Password)
+	assert.Equal(t, "admin", resource.Role)
+	assert.Equal(t, "gf", resource.Short)
+	assert.Equal(t, "75011", resource.Address.PostalCode)
+
+	//
+	// From()
+	//
+
+	user = &ProfileUser{ID: 1}
+	assert.Nil(t, deepcopier.Copy(user).From(&ProfileUserResource{
+		ID:       "user-2",
+		Mail:     "thoas@example.com",
+		Password: "changed",
+	}))
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "thoas@example.com", user.Email)
+	assert.Equal(t, "changed", user.Password)
+}
+
+func TestProfile_Errors(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name int
+		}
+	)
+
+	errConvert := errors.New("cannot convert")
+
+	deepcopier.Register(deepcopier.Profile[Src, Dst]{
+		Converters: map[string]func(interface{}) (interface{}, error){
+			"Name": func(v interface{}) (interface{}, error) {
+				if v == "" {
+					return nil, errConvert
+				}
+				return v, nil
+			},
+		},
+	})
+
+	err := deepcopier.Copy(&Src{}).To(&Dst{})
+	assert.True(t, errors.Is(err, errConvert))
+
+	var fieldErr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Name", fieldErr.Path)
+
+	assert.NotNil(t, deepcopier.Copy(&Src{Name: "gilles"}).To(&Dst{}))
+
+	assert.Panics(t, func() {
+		deepcopier.Register(deepcopier.Profile[Src, Dst]{Skip: []string{"Missing"}})
+	})
+
+	assert.Panics(t, func() {
+		deepcopier.Register(deepcopier.Profile[Src, Dst]{Fields: map[string]string{"Name": "Missing"}})
+	})
+
+	assert.Panics(t, func() {
+		deepcopier.Register(deepcopier.Profile[string, Dst]{})
+	})
+}
+
+func TestProfile_Explain(t *testing.T) {
+	plan := deepcopier.Explain(reflect.TypeOf(ProfileUser{}), reflect.TypeOf(ProfileUserResource{}))
+
+	m, ok := plan.Mapping("Mail")
+	assert.True(t, ok)
+	assert.Equal(t, "Email", m.Source)
+
+	m, ok = plan.Mapping("ID")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionConverter, m.Conversion)
+
+	m, ok = plan.Mapping("Name")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionResolver, m.Conversion)
+
+	m, ok = plan.Mapping("Password")
+	assert.True(t, ok)
+	assert.Equal(t, "skip option", m.Skipped)
+
+	m, ok = plan.Mapping("Address")
+	assert.True(t, ok)
+	assert.NotNil(t, m.Nested)
+
+	m, ok = m.Nested.Mapping("PostalCode")
+	assert.True(t, ok)
+	assert.Equal(t, "ZipCode", m.Source)
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestMapSeq(t *testing.T) {
+	var (
+		labels []string
+		ptrs   []*CollectionRowResource
+	)
+
+	for row, err := range deepcopier.MapSeq[CollectionRow, CollectionRowResource](slices.Values(rows(3))) {
+		assert.Nil(t, err)
+		labels = append(labels, row.Label)
+	}
+
+	assert.Equal(t, []string{"row-0", "row-1", "row-2"}, labels)
+
+	for row, err := range deepcopier.MapSeq[CollectionRow, *CollectionRowResource](slices.Values(rows(2))) {
+		assert.Nil(t, err)
+		ptrs = append(ptrs, row)
+	}
+
+	assert.Equal(t, []*CollectionRowResource{{0, "row-0"}, {1, "row-1"}}, ptrs)
+
+	//
+	// Errors and early stop
+	//
+
+	src := rows(5)
+	src[1].ID = -1
+
+	var errs []error
+	for _, err := range deepcopier.MapSeq[CollectionRow, CollectionRowResource](slices.Values(src)) {
+		errs = append(errs, err)
+		if len(errs) == 3 {
+			break
+		}
+	}
+
+	assert.Len(t, errs, 3)
+	assert.Nil(t, errs[0])
+	assert.True(t, errors.Is(errs[1], errInvalidRow))
+	assert.Nil(t, errs[2])
+
+	//
+	// Nil sources
+	//
+
+	var (
+		row  = rows(1)[0]
+		nils []*CollectionRowResource
+	)
+
+	for dst, err := range deepcopier.MapSeq[*CollectionRow, *CollectionRowResource](slices.Values([]*CollectionRow{nil, &row})) {
+		assert.Nil(t, err)
+		nils = append(nils, dst)
+	}
+
+	assert.Equal(t, []*CollectionRowResource{nil, {0, "row-0"}}, nils)
+
+	for dst, err := range deepcopier.MapSeq[interface{}, CollectionRowResource](slices.Values([]interface{}{nil})) {
+		assert.Nil(t, err)
+		assert.Zero(t, dst)
+	}
+
+	//
+	// Options
+	//
+
+	type Payload struct {
+		Name string `deepcopier:"field:Label"`
+	}
+
+	for row, err := range deepcopier.MapSeq[Payload, CollectionRowResource](slices.Values([]Payload{{Name: "foo"}}), deepcopier.Options{Reversed: true}) {
+		assert.Nil(t, err)
+		assert.Equal(t, "foo", row.Label)
+	}
+}
+
+func TestMapChan(t *testing.T) {
+	ch := make(chan CollectionRow)
+
+	go func() {
+		defer close(ch)
+		for _, row := range rows(100) {
+			ch <- row
+		}
+	}()
+
+	var i int
+	for result := range deepcopier.MapChan[CollectionRow, CollectionRowResource](context.Background(), ch) {
+		assert.Nil(t, result.Err)
+		assert.Equal(t, i, result.Value.ID)
+		i++
+	}
+
+	assert.Equal(t, 100, i)
+
+	//
+	// Nil sources
+	//
+
+	ptrs := make(chan *CollectionRow, 1)
+	ptrs <- nil
+	close(ptrs)
+
+	results := deepcopier.MapChan[*CollectionRow, *CollectionRowResource](context.Background(), ptrs)
+	assert.Equal(t, deepcopier.Result[*CollectionRowResource]{}, <-results)
+
+	//
+	// Cancellation
+	//
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		infinite    = make(chan CollectionRow)
+		done        = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case infinite <- CollectionRow{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	out := deepcopier.MapChan[CollectionRow, *CollectionRowResource](ctx, infinite)
+	<-out
+	cancel()
+
+	for range out {
+	}
+
+	<-done
+}
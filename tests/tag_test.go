@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected deepcopier.TagOptions
+		err      error
+	}{
+		{"", deepcopier.TagOptions{}, nil},
+		{"skip", deepcopier.TagOptions{"skip": ""}, nil},
+		{"skip; force", deepcopier.TagOptions{"skip": "", "force": ""}, nil},
+		{" field : Name ; force ;", deepcopier.TagOptions{"field": "Name", "force": ""}, nil},
+		{"field:a:b", deepcopier.TagOptions{"field": "a:b"}, nil},
+		{"field:'a;b: c'; force", deepcopier.TagOptions{"field": "a;b: c", "force": ""}, nil},
+		{`field:'it\'s'`, deepcopier.TagOptions{"field": "it's"}, nil},
+		{`field:a\;b`, deepcopier.TagOptions{"field": "a;b"}, nil},
+		{"field:'a", deepcopier.TagOptions{"field": "a"}, deepcopier.ErrInvalidTag},
+		{"field:'a' b", deepcopier.TagOptions{"field": "a"}, deepcopier.ErrInvalidTag},
+		{":Name", deepcopier.TagOptions{}, deepcopier.ErrInvalidTag},
+		{"foce", deepcopier.TagOptions{"foce": ""}, deepcopier.ErrUnknownOption},
+		{"field:A; field:B", deepcopier.TagOptions{"field": "B"}, deepcopier.ErrDuplicatedOption},
+	}
+
+	for _, tt := range tests {
+		options, err := deepcopier.ParseTag(tt.tag)
+		assert.Equal(t, tt.expected, options, tt.tag)
+		if tt.err == nil {
+			assert.Nil(t, err, tt.tag)
+		} else {
+			assert.True(t, errors.Is(err, tt.err), tt.tag)
+		}
+	}
+}
+
+func TestTagOptions_List(t *testing.T) {
+	options, err := deepcopier.ParseTag("field:'a, b,c'; force")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, options.List("field"))
+	assert.Nil(t, options.List("force"))
+	assert.Nil(t, options.List("skip"))
+}
+
+func TestTag_TrimmedKeyword(t *testing.T) {
+	type (
+		Src struct {
+			Email sql.NullString
+		}
+
+		Dst struct {
+			Email string `deepcopier:"field:Email; force"`
+		}
+	)
+
+	src := &Src{Email: sql.NullString{Valid: true, String: "gilles@example.com"}}
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(src).To(dst))
+	assert.Equal(t, "gilles@example.com", dst.Email)
+}
+
+func TestTag_Strict(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name string `deepcopier:"feild:Name"`
+		}
+	)
+
+	// Invalid tags are ignored by default
+	assert.Nil(t, deepcopier.Copy(&Src{Name: "gilles"}).To(&Dst{}))
+
+	//
+	// To()
+	//
+
+	err := deepcopier.Copy(&Src{Name: "gilles"}).Strict().To(&Dst{})
+
+	var terr *deepcopier.TagError
+	assert.True(t, errors.As(err, &terr))
+	assert.Equal(t, reflect.TypeOf(Dst{}), terr.Type)
+	assert.Equal(t, "Name", terr.Field)
+	assert.True(t, errors.Is(err, deepcopier.ErrUnknownOption))
+
+	//
+	// From()
+	//
+
+	err = deepcopier.Copy(&Src{}).Strict().From(&Dst{})
+	assert.True(t, errors.Is(err, deepcopier.ErrUnknownOption))
+}
+
+func TestTag_Explain(t *testing.T) {
+	type (
+		Src struct {
+			Name string `deepcopier:"field:A; field:B"`
+		}
+
+		Dst struct {
+			Name string `deepcopier:"foce"`
+		}
+	)
+
+	plan := deepcopier.Explain(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}))
+	assert.Len(t, plan.Errors, 2)
+	assert.True(t, errors.Is(plan.Errors[0], deepcopier.ErrDuplicatedOption))
+	assert.True(t, errors.Is(plan.Errors[1], deepcopier.ErrUnknownOption))
+	assert.Contains(t, plan.String(), `error: invalid deepcopier tag of Dst.Name: unknown option "foce"`)
+}
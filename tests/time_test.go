@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestTime(t *testing.T) {
+	type (
+		Model struct {
+			CreatedAt   time.Time
+			UpdatedAt   time.Time
+			DeletedAt   *time.Time
+			PublishedAt sql.NullTime
+			Birthday    time.Time
+			ExpiresAt   time.Time
+			StartsAt    time.Time
+		}
+
+		Resource struct {
+			CreatedAt   string `deepcopier:"tz:Europe/Paris"`
+			UpdatedAt   int64  `deepcopier:"layout:unixmilli"`
+			DeletedAt   string
+			PublishedAt *time.Time `deepcopier:"force"`
+			Birthday    string     `deepcopier:"layout:DateOnly"`
+			ExpiresAt   int64
+			StartsAt    string `deepcopier:"layout:'2006-01-02 15:04'"`
+		}
+	)
+
+	var (
+		date  = time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+		paris = time.FixedZone("CET", 3600)
+	)
+
+	//
+	// To()
+	//
+
+	model := &Model{
+		CreatedAt:   date,
+		UpdatedAt:   date,
+		PublishedAt: sql.NullTime{Time: date, Valid: true},
+		Birthday:    date,
+		ExpiresAt:   date,
+		StartsAt:    date,
+	}
+
+	resource := &Resource{}
+	assert.Nil(t, deepcopier.Copy(model).To(resource))
+	assert.Equal(t, "2020-01-02T16:04:05+01:00", resource.CreatedAt)
+	assert.Equal(t, date.UnixMilli(), resource.UpdatedAt)
+	assert.Empty(t, resource.DeletedAt)
+	assert.True(t, date.Equal(*resource.PublishedAt))
+	assert.Equal(t, "2020-01-02", resource.Birthday)
+	assert.Equal(t, date.Unix(), resource.ExpiresAt)
+	assert.Equal(t, "2020-01-02 15:04", resource.StartsAt)
+
+	//
+	// From()
+	//
+
+	deletedAt := "2020-01-03T10:00:00Z"
+
+	model = &Model{}
+	assert.Nil(t, deepcopier.Copy(model).From(&Resource{
+		CreatedAt:   "2020-01-02T16:04:05+01:00",
+		UpdatedAt:   date.UnixMilli(),
+		DeletedAt:   deletedAt,
+		PublishedAt: &date,
+		Birthday:    "2020-01-02",
+		ExpiresAt:   date.Unix(),
+		StartsAt:    "2020-01-02 15:04",
+	}))
+	assert.True(t, date.Equal(model.CreatedAt))
+	assert.True(t, date.Equal(model.UpdatedAt))
+	assert.Equal(t, time.Date(2020, 1, 3, 10, 0, 0, 0, time.UTC), *model.DeletedAt)
+	assert.Equal(t, sql.NullTime{Time: date, Valid: true}, model.PublishedAt)
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), model.Birthday)
+	assert.True(t, date.Equal(model.ExpiresAt))
+	assert.Equal(t, time.Date(2020, 1, 2, 15, 4, 0, 0, time.UTC), model.StartsAt)
+
+	//
+	// Context location
+	//
+
+	type Local struct {
+		StartsAt string `deepcopier:"layout:'2006-01-02 15:04'"`
+	}
+
+	local := &Local{}
+	assert.Nil(t, deepcopier.Copy(&Model{StartsAt: date}).
+		WithContext(map[string]interface{}{deepcopier.TimeZoneContextKey: paris}).
+		To(local))
+	assert.Equal(t, "2020-01-02 16:04", local.StartsAt)
+
+	model = &Model{}
+	assert.Nil(t, deepcopier.Copy(model).
+		WithContext(map[string]interface{}{deepcopier.TimeZoneContextKey: paris}).
+		From(local))
+	assert.True(t, time.Date(2020, 1, 2, 15, 4, 0, 0, time.UTC).Equal(model.StartsAt))
+
+	// Location names are loaded once
+	for i := 0; i < 2; i++ {
+		local = &Local{}
+		assert.Nil(t, deepcopier.Copy(&Model{StartsAt: date}).
+			WithContext(map[string]interface{}{deepcopier.TimeZoneContextKey: "Europe/Paris"}).
+			To(local))
+		assert.Equal(t, "2020-01-02 16:04", local.StartsAt)
+	}
+
+	err := deepcopier.Copy(&Model{StartsAt: date}).
+		WithContext(map[string]interface{}{deepcopier.TimeZoneContextKey: "Nowhere/Atlantis"}).
+		Strict().
+		To(&Local{})
+	assert.EqualError(t, err, "StartsAt: unknown time zone Nowhere/Atlantis")
+}
+
+func TestTime_Errors(t *testing.T) {
+	type (
+		Src struct {
+			Date string
+		}
+
+		Dst struct {
+			Date time.Time
+		}
+
+		InvalidZone struct {
+			Date string `deepcopier:"tz:Nowhere/Atlantis"`
+		}
+	)
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{Date: "yesterday"}).To(dst))
+	assert.Zero(t, dst.Date)
+
+	err := deepcopier.Copy(&Src{Date: "yesterday"}).Strict().To(dst)
+	var fieldErr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Date", fieldErr.Path)
+
+	err = deepcopier.Copy(&Dst{}).Strict().To(&InvalidZone{})
+	assert.True(t, errors.Is(err, deepcopier.ErrInvalidTag))
+
+	plan := deepcopier.Explain(reflect.TypeOf(Src{}), reflect.TypeOf(Dst{}))
+	m, ok := plan.Mapping("Date")
+	assert.True(t, ok)
+	assert.Equal(t, deepcopier.ConversionConvert, m.Conversion)
+}
+
+func TestTime_Nil(t *testing.T) {
+	type (
+		Model struct {
+			UpdatedAt time.Time
+			DeletedAt string
+		}
+
+		Payload struct {
+			UpdatedAt *time.Time
+			DeletedAt *time.Time
+		}
+	)
+
+	date := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	// Nil times leave destinations unchanged
+	model := &Model{UpdatedAt: date, DeletedAt: "2020-01-03T10:00:00Z"}
+	assert.Nil(t, deepcopier.Copy(model).From(&Payload{UpdatedAt: nil}))
+	assert.Equal(t, date, model.UpdatedAt)
+	assert.Equal(t, "2020-01-03T10:00:00Z", model.DeletedAt)
+}
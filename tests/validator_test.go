@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/ulule/deepcopier"
+)
+
+func TestValidator(t *testing.T) {
+	type Src struct {
+		Email string
+	}
+
+	//
+	// To()
+	//
+
+	dst := &ValidatorTester{}
+	assert.Nil(t, deepcopier.Copy(&Src{Email: "gilles@example.com"}).To(dst))
+	assert.Equal(t, "gilles@example.com", dst.Email)
+
+	dst = &ValidatorTester{}
+	err := deepcopier.Copy(&Src{}).To(dst)
+
+	var verr *deepcopier.ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, errEmptyEmail, verr.Err)
+
+	//
+	// From()
+	//
+
+	dst = &ValidatorTester{}
+	err = deepcopier.Copy(dst).From(&Src{})
+	assert.True(t, errors.Is(err, errEmptyEmail))
+}
+
+func TestValidator_Nested(t *testing.T) {
+	type (
+		Owner struct {
+			Email string
+		}
+
+		Src struct {
+			Rel struct {
+				Owner Owner
+			}
+		}
+
+		Dst struct {
+			Rel struct {
+				Owner ValidatorTester
+			}
+		}
+	)
+
+	err := deepcopier.Copy(&Src{}).To(&Dst{})
+
+	var ferr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "Rel.Owner", ferr.Path)
+	assert.True(t, errors.Is(err, errEmptyEmail))
+}
+
+func TestValidator_Func(t *testing.T) {
+	type (
+		Src struct {
+			Name string
+		}
+
+		Dst struct {
+			Name string
+		}
+	)
+
+	errEmptyName := errors.New("empty name")
+	validator := func(dst interface{}) error {
+		if dst.(*Dst).Name == "" {
+			return &deepcopier.FieldError{Path: "Name", Err: errEmptyName}
+		}
+		return nil
+	}
+
+	//
+	// To()
+	//
+
+	dst := &Dst{}
+	assert.Nil(t, deepcopier.Copy(&Src{Name: "gilles"}).WithValidator(validator).To(dst))
+
+	dst = &Dst{}
+	err := deepcopier.Copy(&Src{}).WithValidator(validator).To(dst)
+
+	var ferr *deepcopier.FieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "Name", ferr.Path)
+	assert.True(t, errors.Is(err, errEmptyName))
+
+	//
+	// From()
+	//
+
+	dst = &Dst{}
+	err = deepcopier.Copy(dst).WithValidator(validator).From(&Src{})
+	assert.True(t, errors.Is(err, errEmptyName))
+}
+
+// ----------------------------------------------------------------------------
+// Validator testers
+// ----------------------------------------------------------------------------
+
+var errEmptyEmail = errors.New("empty email")
+
+type ValidatorTester struct {
+	Email string
+}
+
+func (v *ValidatorTester) Validate() error {
+	if v.Email == "" {
+		return errEmptyEmail
+	}
+	return nil
+}
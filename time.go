@@ -0,0 +1,292 @@
+package deepcopier
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// TimeZoneContextKey is the context key of the location used by time
+	// conversions of fields without tz option (a *time.Location or a
+	// location name).
+	TimeZoneContextKey = "tz"
+
+	// UnixLayout is the layout of Unix times in seconds.
+	UnixLayout = "unix"
+	// UnixMilliLayout is the layout of Unix times in milliseconds.
+	UnixMilliLayout = "unixmilli"
+)
+
+// layouts are time layouts which can be given by name to the layout option.
+var layouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// isTimeConversion returns true if values of the given types are converted
+// by convertTime: times (time.Time, *time.Time or nullable times) to other
+// times, strings or integers, and strings or integers to times.
+func isTimeConversion(src reflect.Type, dst reflect.Type) bool {
+	if src.AssignableTo(dst) {
+		return false
+	}
+
+	switch {
+	case isTimeType(src):
+		return isTimeType(dst) || isTimeScalar(dst)
+	case isTimeType(dst):
+		return isTimeScalar(src)
+	}
+
+	return false
+}
+
+// isTimeType returns true if the given type is time.Time, *time.Time or a
+// nullable time (sql.NullTime, mysql.NullTime...).
+func isTimeType(t reflect.Type) bool {
+	return t == timeType || t == reflect.PtrTo(timeType) || isNullableTime(t)
+}
+
+// isNullableTime returns true if the given type is a nullable type with a
+// Time field.
+func isNullableTime(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || !isNullableType(t) {
+		return false
+	}
+
+	f, ok := t.FieldByName("Time")
+
+	return ok && f.Type == timeType
+}
+
+// isTimeScalar returns true if times are converted to and from values of the
+// given type: strings and integers (but durations).
+func isTimeScalar(t reflect.Type) bool {
+	return t.Kind() == reflect.String || ((isInt(t.Kind()) || isUint(t.Kind())) && t != durationType)
+}
+
+// convertTime converts the given time, string or Unix time to the given type.
+// Strings are formatted and parsed with the layout option (RFC3339 by default)
+// and the given location of the tz option or the location of the context.
+// Zero times, empty strings and zero integers are null times. It returns false
+// for nil *time.Time, which leave destinations unchanged.
+func convertTime(value reflect.Value, t reflect.Type, tagOptions TagOptions, tz *time.Location, options Options) (reflect.Value, bool, error) {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return reflect.Value{}, false, nil
+	}
+
+	loc, err := getLocation(tagOptions, tz, options)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+
+	layout := getLayout(tagOptions)
+
+	tm, valid, err := getTime(value, layout, loc)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+
+	if valid && loc != nil {
+		tm = tm.In(loc)
+	}
+
+	if !valid {
+		return reflect.Zero(t), true, nil
+	}
+
+	switch {
+	case t == timeType:
+		return reflect.ValueOf(tm), true, nil
+	case t == reflect.PtrTo(timeType):
+		return reflect.ValueOf(&tm), true, nil
+	case isNullableTime(t):
+		ptr := reflect.New(t)
+
+		scanner, ok := ptr.Interface().(sql.Scanner)
+		if !ok {
+			return reflect.Value{}, false, fmt.Errorf("%s is not a sql.Scanner", t)
+		}
+
+		if err := scanner.Scan(tm); err != nil {
+			return reflect.Value{}, false, err
+		}
+
+		return ptr.Elem(), true, nil
+	case t.Kind() == reflect.String:
+		var s string
+
+		switch layout {
+		case UnixLayout:
+			s = strconv.FormatInt(tm.Unix(), 10)
+		case UnixMilliLayout:
+			s = strconv.FormatInt(tm.UnixMilli(), 10)
+		default:
+			s = tm.Format(layout)
+		}
+
+		return reflect.ValueOf(s).Convert(t), true, nil
+	}
+
+	n := tm.Unix()
+	if layout == UnixMilliLayout {
+		n = tm.UnixMilli()
+	}
+
+	v, ok := convertInt(n, t)
+	if !ok {
+		return reflect.Value{}, false, fmt.Errorf("time %s overflows %s", tm, t)
+	}
+
+	return v, true, nil
+}
+
+// getTime returns the time of the given value, false if it is a null time.
+func getTime(value reflect.Value, layout string, loc *time.Location) (time.Time, bool, error) {
+	switch t := value.Type(); {
+	case t == timeType:
+		tm := value.Interface().(time.Time)
+		return tm, !tm.IsZero(), nil
+	case t == reflect.PtrTo(timeType):
+		if value.IsNil() {
+			return time.Time{}, false, nil
+		}
+		return getTime(value.Elem(), layout, loc)
+	case isNullableTime(t):
+		if valid := value.FieldByName("Valid"); valid.IsValid() && valid.Kind() == reflect.Bool && !valid.Bool() {
+			return time.Time{}, false, nil
+		}
+		return getTime(value.FieldByName("Time"), layout, loc)
+	case t.Kind() == reflect.String:
+		s := value.String()
+		if s == "" {
+			return time.Time{}, false, nil
+		}
+
+		if layout == UnixLayout || layout == UnixMilliLayout {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return time.Time{}, false, fmt.Errorf("invalid Unix time %q", s)
+			}
+			return unixTime(n, layout), true, nil
+		}
+
+		if loc == nil {
+			loc = time.UTC
+		}
+
+		tm, err := time.ParseInLocation(layout, s, loc)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+
+		return tm, true, nil
+	case isInt(t.Kind()):
+		n := value.Int()
+		return unixTime(n, layout), n != 0, nil
+	case isUint(t.Kind()):
+		n := value.Uint()
+		return unixTime(int64(n), layout), n != 0, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("cannot convert %s to time", value.Type())
+}
+
+// unixTime returns the UTC time of the given Unix time, in milliseconds with
+// the unixmilli layout and in seconds otherwise.
+func unixTime(n int64, layout string) time.Time {
+	if layout == UnixMilliLayout {
+		return time.UnixMilli(n).UTC()
+	}
+	return time.Unix(n, 0).UTC()
+}
+
+// getLayout returns the time layout of the layout option.
+func getLayout(tagOptions TagOptions) string {
+	layout := tagOptions[LayoutOptionName]
+	if layout == "" {
+		return time.RFC3339
+	}
+
+	if l, ok := layouts[layout]; ok {
+		return l
+	}
+
+	return layout
+}
+
+// loadLocation returns the location of the tz option, nil if it is missing or
+// unknown.
+func loadLocation(tagOptions TagOptions) *time.Location {
+	name := tagOptions[TimeZoneOptionName]
+	if name == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+
+	return loc
+}
+
+// getLocation returns the given location of the tz option, the location of
+// the context, or nil.
+func getLocation(tagOptions TagOptions, tz *time.Location, options Options) (*time.Location, error) {
+	if name := tagOptions[TimeZoneOptionName]; name != "" {
+		if tz == nil {
+			return nil, fmt.Errorf("unknown time zone %s", name)
+		}
+		return tz, nil
+	}
+
+	v, ok := lookupContext(TimeZoneContextKey, options)
+	if !ok {
+		return nil, nil
+	}
+
+	switch v := v.(type) {
+	case *time.Location:
+		return v, nil
+	case string:
+		return contextLocation(v)
+	}
+
+	return nil, fmt.Errorf("invalid %s context value %v", TimeZoneContextKey, v)
+}
+
+// contextLocations caches locations loaded from location names of the tz
+// context value, which would otherwise be loaded for each converted field.
+var contextLocations sync.Map
+
+// contextLocation returns the location of the given name of the tz context
+// value.
+func contextLocation(name string) (*time.Location, error) {
+	if loc, ok := contextLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	contextLocations.Store(name, loc)
+
+	return loc, nil
+}
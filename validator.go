@@ -0,0 +1,24 @@
+package deepcopier
+
+// Validator is implemented by destinations that validate themselves
+// after being copied.
+type Validator interface {
+	Validate() error
+}
+
+// ValidatorFunc validates the destination after copy.
+type ValidatorFunc func(dst interface{}) error
+
+// validate calls the Validate() method of the given destination.
+func validate(dst interface{}) error {
+	v, ok := dst.(Validator)
+	if !ok {
+		return nil
+	}
+
+	if err := v.Validate(); err != nil {
+		return &ValidationError{Err: err}
+	}
+
+	return nil
+}